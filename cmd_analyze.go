@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runAnalyzeIntro executa "player4k analyze-intro [opções] <pasta>"
+func runAnalyzeIntro(args []string) int {
+	fs := newFlagSet("analyze-intro", "analyze-intro [-json] [-force] <pasta>",
+		"Compara o áudio dos episódios de uma pasta e encontra abertura e encerramento de cada um.\nO resultado fica no cache (intros.json) e é usado pelo play quando o arquivo não tem capítulos.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+	force := fs.Bool("force", false, "Analisar de novo episódios que já estão no cache")
+	window := fs.Float64("window", player.DefaultIntroOptions().Window, "Segundos analisados no começo e no fim de cada episódio")
+	minLength := fs.Float64("min", player.DefaultIntroOptions().MinLength, "Duração mínima da abertura/encerramento em segundos")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) != 1 || *window <= 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	paths, err := player.EpisodePlaylist(rest[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if len(paths) < 2 {
+		fmt.Fprintf(os.Stderr, "❌ São necessários pelo menos 2 episódios em %s\n", rest[0])
+		return exitError
+	}
+
+	cache, err := player.OpenDefaultIntroCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if !*force && len(cache.Missing(paths)) == 0 {
+		fmt.Fprintln(os.Stderr, "✓ Todos os episódios já estão no cache (use -force para analisar de novo)")
+		return printIntroCache(cache, paths, *asJSON)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := player.DefaultIntroOptions()
+	opts.Window = *window
+	opts.MinLength = *minLength
+	opts.Progress = func(done, total int, path string) {
+		fmt.Fprintf(os.Stderr, "🎵 [%d/%d] %s\n", done+1, total, filepath.Base(path))
+	}
+
+	result, err := player.AnalyzeIntros(ctx, paths, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	cache.Store(result)
+	if err := cache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "[Player4K] Aviso: não foi possível salvar o cache: %v\n", err)
+	}
+
+	if *asJSON {
+		return printJSON(result)
+	}
+	for _, ep := range result.Episodes {
+		printEpisodeIntro(ep.Path, ep.Segments, ep.Error)
+	}
+	fmt.Printf("\n✓ Encontrado em %d de %d episódios\n", result.Found(), len(result.Episodes))
+	return exitOK
+}
+
+// printIntroCache mostra o que já está no cache para os episódios da pasta
+func printIntroCache(cache *player.IntroCache, paths []string, asJSON bool) int {
+	result := &player.IntroAnalysis{}
+	for _, path := range paths {
+		key := player.MediaKey(path)
+		segments, _ := cache.Segments(key)
+		result.Episodes = append(result.Episodes, player.EpisodeIntro{Path: path, Key: key, Segments: segments})
+	}
+
+	if asJSON {
+		return printJSON(result)
+	}
+	for _, ep := range result.Episodes {
+		printEpisodeIntro(ep.Path, ep.Segments, "")
+	}
+	return exitOK
+}
+
+// printEpisodeIntro mostra os trechos de um episódio numa linha
+func printEpisodeIntro(path string, segments []player.SkipSegment, errMsg string) {
+	line := "📺 " + player.CleanTitle(path)
+	switch {
+	case errMsg != "":
+		line += "  ❌ " + errMsg
+	case len(segments) == 0:
+		line += "  (nada encontrado)"
+	}
+	for _, seg := range segments {
+		line += fmt.Sprintf("  %s %s–%s", seg.Kind, formatTime(seg.Start), formatTime(seg.End))
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runBench executa "player4k bench": mede cada preset e grava o modo recomendado
+func runBench(args []string) int {
+	fs := newFlagSet("bench", "bench [-seconds=N] [-null] [-no-save]",
+		"Reproduz um clipe de teste com cada preset e recomenda o modo mais pesado que a GPU aguenta.")
+	seconds := fs.Int("seconds", 5, "Segundos medidos por preset")
+	null := fs.Bool("null", false, "Usar vo=null (máquina sem GPU)")
+	noSave := fs.Bool("no-save", false, "Não gravar o modo recomendado")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 0 || *seconds <= 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	p, err := player.New()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitError
+	}
+	defer p.Close()
+
+	opts := player.DefaultBenchmarkOptions()
+	opts.Duration = time.Duration(*seconds) * time.Second
+	opts.NullOutput = *null
+	opts.Persist = !*noSave
+
+	result, err := p.Benchmark(opts)
+	if err != nil {
+		fmt.Printf("❌ Benchmark falhou: %v\n", err)
+		if result == nil {
+			return exitError
+		}
+	}
+
+	fmt.Println("\n📊 Resultado (orçamento por frame:", fmt.Sprintf("%.2f ms)", result.FrameBudgetMs))
+	fmt.Println("─────────────────────────────────────")
+	for _, r := range result.Runs {
+		if r.Error != "" {
+			fmt.Printf("  %-10s ✗ %s\n", r.Mode, r.Error)
+			continue
+		}
+		fmt.Printf("  %-10s %6.2f ms/frame (pico %.2f)  perdidos: %d  atrasados: %d  vo: %s\n",
+			r.Mode, r.FrameTimeMs, r.PeakFrameTimeMs, r.DroppedFrames, r.DelayedFrames, r.VO)
+	}
+	fmt.Println("─────────────────────────────────────")
+	fmt.Printf("🏁 Recomendado: %s\n   %s\n", result.Recommended, result.Reason)
+	if err != nil {
+		return exitError
+	}
+	if opts.Persist {
+		path, _ := player.UserConfigPath()
+		fmt.Printf("💾 Salvo em %s\n", path)
+	}
+	return exitOK
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// settingFlags acumula os "-set chave=valor" da linha de comando
+type settingFlags []string
+
+func (f *settingFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *settingFlags) Set(s string) error {
+	if !strings.Contains(s, "=") {
+		return fmt.Errorf("use chave=valor (ex: osd.fontSize=40)")
+	}
+	*f = append(*f, s)
+	return nil
+}
+
+// flagSettings liga as flags antigas do play às chaves da configuração
+var flagSettings = map[string]string{
+	"mode":     "playback.mode",
+	"volume":   "playback.volume",
+	"fs":       "playback.fullscreen",
+	"adaptive": "playback.adaptive",
+	"resume":   "playback.resume",
+	"anime":    "presets.anime",
+	"bundle":   "playback.configBundle",
+}
+
+// applySettingFlags aplica a camada da linha de comando: as flags informadas e os -set
+func applySettingFlags(fs *flag.FlagSet, settings *player.Settings, sets settingFlags) error {
+	var problems []string
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := flagSettings[f.Name]; ok {
+			if err := settings.Set(key, f.Value.String(), player.SourceFlag); err != nil {
+				problems = append(problems, fmt.Sprintf("-%s: %v", f.Name, err))
+			}
+		}
+	})
+	for _, kv := range sets {
+		key, value, _ := strings.Cut(kv, "=")
+		if err := settings.Set(strings.TrimSpace(key), value, player.SourceFlag); err != nil {
+			problems = append(problems, "-set "+err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// runConfig executa "player4k config show|path"
+func runConfig(args []string) int {
+	if len(args) == 0 {
+		printConfigUsage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "show":
+		return runConfigShow(args[1:])
+	case "bundle":
+		return runConfigBundle(args[1:])
+	case "path":
+		path, err := player.UserConfigPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+		fmt.Println(path)
+		return exitOK
+	case "-h", "-help", "--help":
+		printConfigUsage()
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Subcomando desconhecido: config %s\n", args[0])
+	printConfigUsage()
+	return exitUsage
+}
+
+func printConfigUsage() {
+	fmt.Println(`📖 USO: player4k config <show|bundle|path>
+
+   show     Mostrar as opções definidas no player4k.json (--effective: todas, com a origem)
+   bundle   Mostrar a pasta de configuração do MPV e o que cada modo troca no mpv.conf
+   path     Mostrar o caminho do player4k.json`)
+}
+
+// runConfigShow executa "player4k config show [--effective] [-json]"
+func runConfigShow(args []string) int {
+	fs := newFlagSet("config show", "config show [--effective] [-json] [-set chave=valor]...",
+		"Mostra a configuração em camadas: embutida < player4k.json < variáveis PLAYER4K_* < flags.\nSem --effective, só as opções que não são as embutidas.")
+	effective := fs.Bool("effective", false, "Mostrar todas as opções com o valor efetivo e a origem")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+	var sets settingFlags
+	fs.Var(&sets, "set", "Simular uma flag -set do play (pode repetir)")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	settings, err := player.LoadSettings()
+	code = exitOK
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		code = exitError
+	}
+	if err := applySettingFlags(fs, settings, sets); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitUsage
+	}
+
+	values := settings.Values()
+	if !*effective {
+		shown := values[:0]
+		for _, v := range values {
+			if v.Source != player.SourceBuiltin {
+				shown = append(shown, v)
+			}
+		}
+		values = shown
+	}
+
+	if *asJSON {
+		if *effective {
+			if c := printJSON(settings); c != exitOK {
+				return c
+			}
+			return code
+		}
+		if c := printJSON(values); c != exitOK {
+			return c
+		}
+		return code
+	}
+
+	if len(values) == 0 {
+		fmt.Println("Nenhuma opção alterada (use --effective para ver todas)")
+		return code
+	}
+	section := ""
+	for _, v := range values {
+		s, _, _ := strings.Cut(v.Key, ".")
+		if s != section {
+			if section != "" {
+				fmt.Println()
+			}
+			fmt.Printf("[%s]\n", s)
+			section = s
+		}
+		fmt.Printf("  %-28s %-24s (%s)\n", v.Key, quoteSetting(v.Value), sourceLabel(v.Source))
+	}
+	return code
+}
+
+// runConfigBundle executa "player4k config bundle [-json] [portable|user|none]"
+func runConfigBundle(args []string) int {
+	fs := newFlagSet("config bundle", "config bundle [-json] [portable|user|none]",
+		"Mostra a pasta de configuração do MPV (a de playback.configBundle, se nenhuma for informada),\nos scripts encontrados e as opções do mpv.conf que o sistema ou cada modo de qualidade substituem.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var kind player.ConfigBundleKind
+	if len(rest) == 1 {
+		if kind, ok = player.ParseConfigBundleKind(rest[0]); !ok {
+			fmt.Fprintf(os.Stderr, "❌ Pacote desconhecido: %s (use portable, user ou none)\n", rest[0])
+			return exitUsage
+		}
+	} else {
+		settings, err := player.LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		}
+		kind = settings.Playback.ConfigBundle
+	}
+
+	bundle, err := player.OpenConfigBundle(kind)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	// Conflitos por modo: o que cada preset troca do mpv.conf ao ser ativado
+	modes := map[string][]player.BundleConflict{}
+	for _, preset := range player.GetAllPresets() {
+		modes[string(preset.ID)] = bundle.Conflicts(preset.Properties, "modo "+string(preset.ID))
+	}
+
+	if *asJSON {
+		return printJSON(struct {
+			*player.ConfigBundle
+			Platform []player.BundleConflict            `json:"platform"`
+			Modes    map[string][]player.BundleConflict `json:"modes"`
+		}{bundle, bundle.PlatformConflicts(), modes})
+	}
+
+	if !bundle.Active() {
+		fmt.Println("Sem pacote: o MPV usa só a configuração aplicada pelo player")
+		return exitOK
+	}
+	fmt.Printf("📄 %s (%s)\n", bundle.Dir, bundle.Kind)
+	fmt.Printf("   %d opções no mpv.conf, input.conf: %s\n", len(bundle.Options), boolLabel(bundle.HasInput))
+	if bundle.SkipKey != "" {
+		fmt.Printf("   Pular abertura/encerramento: tecla %s\n", bundle.SkipKey)
+	}
+	if len(bundle.Scripts) > 0 {
+		fmt.Printf("   Scripts: %s\n", strings.Join(bundle.Scripts, ", "))
+	}
+	if osc, ok := bundle.Option("osc"); ok && osc == "no" {
+		fmt.Println("   OSC padrão desligado (interface do pacote, ex: uosc)")
+	}
+
+	for _, c := range bundle.PlatformConflicts() {
+		fmt.Printf("⚠️ %s\n", c)
+	}
+	for _, preset := range player.GetAllPresets() {
+		conflicts := modes[string(preset.ID)]
+		if len(conflicts) == 0 {
+			fmt.Printf("%s %-8s sem conflitos\n", preset.Icon, preset.ID)
+			continue
+		}
+		fmt.Printf("%s %-8s substitui %d opções:\n", preset.Icon, preset.ID, len(conflicts))
+		for _, c := range conflicts {
+			fmt.Printf("     %-22s %s → %s\n", c.Property, c.Bundle, c.Value)
+		}
+	}
+	return exitOK
+}
+
+// boolLabel traduz um booleano para sim/não
+func boolLabel(b bool) string {
+	if b {
+		return "sim"
+	}
+	return "não"
+}
+
+// quoteSetting mostra textos vazios ou com espaços entre aspas
+func quoteSetting(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t") {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
+
+// sourceLabel traduz a origem de um valor
+func sourceLabel(src player.SettingSource) string {
+	switch src {
+	case player.SourceFile:
+		return "player4k.json"
+	case player.SourceEnv:
+		return "ambiente"
+	case player.SourceFlag:
+		return "linha de comando"
+	}
+	return "embutido"
+}
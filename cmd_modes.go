@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runModes executa "player4k modes [-json]"
+func runModes(args []string) int {
+	fs := newFlagSet("modes", "modes [-json]", "Lista os modos de qualidade (inclui presets/*.json) e os presets Anime4K.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	if *asJSON {
+		return printJSON(struct {
+			Modes []player.ModeInfo      `json:"modes"`
+			Anime []player.AnimePipeline `json:"anime"`
+		}{player.GetAllModes(), player.GetAnimePipelines()})
+	}
+
+	printBanner()
+	fmt.Println("\n🎬 Modos de Qualidade Disponíveis:")
+	fmt.Println("─────────────────────────────────────")
+	for _, mode := range player.GetAllModes() {
+		fmt.Printf("\n  %s %s (%s)\n", mode.Icon, mode.Name, mode.ID)
+		fmt.Printf("     📝 %s\n", mode.Description)
+		fmt.Printf("     🎮 GPU: %s\n", mode.GPURequired)
+	}
+	fmt.Println("\n🎌 Presets Anime4K (-anime=ID):")
+	fmt.Println("─────────────────────────────────────")
+	for _, pl := range player.GetAnimePipelines() {
+		fmt.Printf("  %-10s %s\n", pl.ID, pl.Description)
+	}
+	fmt.Println("\n─────────────────────────────────────")
+	printControls()
+	return exitOK
+}
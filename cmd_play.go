@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runPlay executa "player4k play [opções] <arquivo>"
+func runPlay(args []string) int {
+	fs := newFlagSet("play", "play [opções] <arquivo_de_video|pasta|playlist.m3u> [mais arquivos...]",
+		"Reproduz arquivos locais ou URLs com upscaling. Vários arquivos, uma pasta (em ordem de episódio)\nou uma playlist .m3u/.m3u8 viram uma fila que avança sozinha para o próximo episódio.")
+	return playWith(fs, "", args)
+}
+
+// runServe executa "player4k serve": player controlado pelo GoAnimeGUI via JSON-RPC
+func runServe(args []string) int {
+	fs := newFlagSet("serve", "serve [-rpc=caminho] [-wid=HANDLE] [opções de play] [arquivo]",
+		"Inicia o player fora do processo do GUI, controlado pelo socket JSON-RPC 2.0.")
+	return playWith(fs, player.DefaultRPCPath(), args)
+}
+
+// playWith registra as opções de reprodução e executa o player
+// rpcDefault vazio deixa o servidor JSON-RPC desligado (play); serve o liga
+func playWith(fs *flag.FlagSet, rpcDefault string, args []string) int {
+	fs.String("mode", "", "Modo de qualidade (veja \"player4k modes\"; padrão: resultado do bench ou medium)")
+	var anime animeFlag
+	fs.Var(&anime, "anime", "Ativar Anime4K: -anime (Modo A HQ) ou -anime=A-HQ|B-Fast|C+A-HQ...")
+	titleFlag := fs.String("title", "", "Título para exibir na janela")
+	subFlag := fs.String("sub", "", "URL ou caminho de legenda externa")
+	listModes := fs.Bool("list-modes", false, "Listar todos os modos disponíveis (igual a \"player4k modes\")")
+	fs.Bool("fs", false, "Iniciar em tela cheia")
+	fs.Int("volume", 100, "Volume inicial (0-100)")
+	startPos := fs.Float64("start", 0, "Posição inicial em segundos")
+	fs.Bool("adaptive", false, "Ajustar a qualidade automaticamente quando houver frames perdidos")
+	ipcPath := fs.String("ipc", player.DefaultIPCPath(), "Socket/pipe usado por \"player4k remote\" (vazio desativa)")
+	rpcPath := fs.String("rpc", rpcDefault, "Socket do servidor JSON-RPC para o GoAnimeGUI (vazio desativa)")
+	wid := fs.Int64("wid", 0, "Handle da janela onde o vídeo será renderizado")
+	alang := fs.String("alang", "", "Idiomas de áudio em ordem de preferência (ex: jpn,pt-BR)")
+	slang := fs.String("slang", "", "Idiomas de legenda em ordem de preferência (ex: pt-BR,en; \"no\" desliga)")
+	series := fs.String("series", "", "Nome da série (escolhe as preferências de idioma e o perfil da série)")
+	loop := fs.String("loop", "none", "Repetição da fila: none, file ou playlist")
+	shuffle := fs.Bool("shuffle", false, "Embaralhar a fila")
+	noAdvance := fs.Bool("no-advance", false, "Parar no fim de cada episódio em vez de avançar")
+	skipMode := fs.String("skip", "", "Abertura/encerramento: prompt (aviso com a tecla de pular), auto ou off (padrão: player4k.json ou prompt)")
+	skipFile := fs.String("skip-file", "", "JSON no formato do AniSkip com os trechos do primeiro arquivo")
+	analyzeIntro := fs.Bool("analyze-intro", false, "Analisar em segundo plano a abertura/encerramento dos episódios da fila ainda fora do cache")
+	fs.Bool("resume", false, "Continuar cada episódio de onde parou (ignorado com -start)")
+	fs.String("bundle", "", "Pasta de configuração do MPV (mpv.conf, uosc, goanime.lua): portable, user ou none (padrão: player4k.json ou portable)")
+	profile := fs.Bool("profile", true, "Aplicar e atualizar o perfil da série (modo, áudio, atraso da legenda, volume)")
+	var sets settingFlags
+	fs.Var(&sets, "set", "Trocar uma opção da configuração: -set osd.fontSize=40 (pode repetir; veja \"player4k config show\")")
+
+	files, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	if *listModes {
+		return runModes(nil)
+	}
+
+	// Configuração em camadas: embutida < player4k.json < PLAYER4K_* < flags
+	settings, err := player.LoadSettings()
+	if err != nil {
+		fmt.Printf("[Player4K] Aviso: %v\n", err)
+	}
+	if err := applySettingFlags(fs, settings, sets); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitUsage
+	}
+
+	// Sem arquivo só faz sentido quando o GUI vai mandar Load pelo RPC
+	if len(files) == 0 && *rpcPath == "" {
+		printBanner()
+		fs.Usage()
+		printControls()
+		return exitUsage
+	}
+
+	// Pasta de configuração do MPV: o MPV carrega mpv.conf, input.conf, scripts e fontes dela
+	bundle, err := player.OpenConfigBundleOrNone(settings.Playback.ConfigBundle)
+	if err != nil {
+		fmt.Printf("[Player4K] Aviso: %v; seguindo sem pacote de configuração\n", err)
+	}
+
+	// Criar instância do player
+	p, err := player.NewWithBundle(bundle)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitError
+	}
+	defer p.Close()
+
+	// Sem pacote, usa os atalhos e o OSC soltos ao lado do executável
+	// (com pacote, input.conf e a interface uosc vêm dele)
+	if !bundle.Active() {
+		execPath, _ := os.Executable()
+		execDir := filepath.Dir(execPath)
+
+		// Carregar atalhos customizados (input.conf)
+		inputConf := filepath.Join(execDir, "input.conf")
+		if _, err := os.Stat(inputConf); err == nil {
+			p.LoadInputConfig(inputConf)
+		}
+
+		// Carregar script OSC (barra de controles na tela)
+		oscScript := filepath.Join(execDir, "scripts", "osc.lua")
+		if _, err := os.Stat(oscScript); err == nil {
+			p.LoadScript(oscScript)
+		}
+	}
+
+	// Controle remoto (player4k remote)
+	if *ipcPath != "" {
+		if err := p.EnableIPC(*ipcPath); err != nil {
+			fmt.Printf("[Player4K] Aviso: controle remoto indisponível: %v\n", err)
+		}
+	}
+
+	// Servidor JSON-RPC (GoAnimeGUI fora do processo)
+	if *rpcPath != "" {
+		l, err := player.ListenRPC(*rpcPath)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return exitError
+		}
+		server := player.NewRPCServer(player.WrapPlayer(p))
+		defer server.Close()
+		go server.Serve(l)
+		fmt.Printf("🔗 Controle JSON-RPC em %s\n", *rpcPath)
+	}
+
+	if *wid != 0 {
+		p.SetWindowHandle(*wid)
+	}
+
+	// Modo, Anime4K, volume, qualidade automática, OSD, legendas, cache...
+	// Sem playback.mode (ou -mode), usa o recomendado pelo bench
+	if settings.Playback.Mode == "" {
+		settings.Playback.Mode = p.AutoSelectMode()
+	}
+	if err := p.ApplySettings(settings); err != nil {
+		fmt.Printf("[Player4K] Aviso: %v\n", err)
+	}
+
+	// Preferências de áudio/legenda (player4k.json, com -alang/-slang por cima)
+	p.SetLanguagePreferences(languagePreferences(*alang, *slang))
+	p.SetSeries(*series)
+
+	// Pular abertura/encerramento (player4k.json, com -skip por cima)
+	skipPrefs, err := skipPreferences(*skipMode)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitUsage
+	}
+	p.SetSkipPreferences(skipPrefs)
+
+	// Aberturas/encerramentos detectados por áudio (player4k analyze-intro)
+	intros, err := player.OpenDefaultIntroCache()
+	if err != nil {
+		fmt.Printf("[Player4K] Aviso: cache de aberturas ignorado: %v\n", err)
+	} else {
+		p.SetIntroCache(intros)
+	}
+
+	// Histórico de reprodução (history.json); -resume continua de onde parou
+	if history, err := player.OpenDefaultWatchHistory(); err != nil {
+		fmt.Printf("[Player4K] Aviso: histórico desligado: %v\n", err)
+	} else {
+		p.SetWatchHistory(history)
+	}
+	if *startPos > 0 {
+		p.SetAutoResume(false)
+	}
+
+	// Perfis por série (profiles.json); -profile=false usa só as opções acima
+	if *profile {
+		if profiles, err := player.OpenDefaultProfileStore(); err != nil {
+			fmt.Printf("[Player4K] Aviso: perfis por série desligados: %v\n", err)
+		} else {
+			p.SetProfileStore(profiles)
+		}
+	}
+
+	if len(files) == 0 {
+		// Aguarda comandos do GUI até Destroy/quit
+		return runLoop(p)
+	}
+
+	// Montar a fila (arquivos soltos e/ou playlists .m3u)
+	entries, err := expandPlaylists(files)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitError
+	}
+	queue := make([]string, len(entries))
+	for i, e := range entries {
+		queue[i] = e.Filename
+	}
+
+	// Define título da janela (sem -title, acompanha o episódio: "▶ Frieren - 05 - GoAnime Player")
+	switch {
+	case len(queue) > 1 && *titleFlag != "":
+		p.SetWindowTitle(*titleFlag)
+	case *titleFlag != "":
+		p.SetTitle(*titleFlag)
+	default:
+		p.SetAutoTitle(windowTitleFormat)
+		first := entries[0].Title
+		if first == "" {
+			first = player.CleanTitle(queue[0])
+		}
+		p.SetWindowTitle(fmt.Sprintf(windowTitleFormat, first))
+	}
+
+	if err := p.LoadEntries(entries...); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return exitError
+	}
+	if len(queue) > 1 {
+		fmt.Printf("📺 Fila com %d episódios\n", len(queue))
+	}
+
+	// Repetição, ordem e avanço automático
+	if err := p.Playlist().SetLoop(player.LoopMode(*loop)); err != nil {
+		fmt.Printf("[Player4K] Aviso: %v\n", err)
+	}
+	if *shuffle {
+		p.Playlist().Shuffle()
+	}
+	if *noAdvance {
+		p.SetAutoAdvance(false)
+	}
+
+	// Aberturas/encerramentos por áudio (arquivos sem capítulos)
+	if *analyzeIntro && intros != nil {
+		if missing := intros.Missing(queue); len(missing) > 0 {
+			if err := p.AnalyzeIntrosAsync(queue); err != nil {
+				fmt.Printf("[Player4K] Aviso: %v\n", err)
+			}
+		}
+	}
+
+	// Trechos do AniSkip para o primeiro arquivo
+	if *skipFile != "" {
+		if segments, err := player.LoadAniSkipFile(*skipFile); err != nil {
+			fmt.Printf("[Player4K] Aviso: %v\n", err)
+		} else {
+			p.SetSkipSegments(segments)
+		}
+	}
+
+	// Carregar legenda externa se fornecida
+	if *subFlag != "" {
+		if err := p.LoadSubtitle(*subFlag); err != nil {
+			fmt.Printf("[Player4K] Aviso: não foi possível carregar legenda: %v\n", err)
+		}
+	}
+
+	// Posição inicial
+	if *startPos > 0 {
+		p.Seek(*startPos)
+	}
+
+	// Loop de eventos
+	return runLoop(p)
+}
+
+// runLoop executa o loop de eventos até o MPV fechar ou chegar Ctrl+C/SIGTERM
+func runLoop(p *player.Player) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := p.Run(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Printf("❌ %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// windowTitleFormat é o título da janela; %s recebe o título limpo do episódio
+const windowTitleFormat = "▶ %s - GoAnime Player"
+
+// expandPlaylists troca pastas e playlists .m3u/.m3u8 locais pelos episódios que elas contêm
+// Itens de .m3u mantêm o título do #EXTINF
+func expandPlaylists(args []string) ([]player.PlaylistEntry, error) {
+	var queue []player.PlaylistEntry
+	for _, arg := range args {
+		if st, err := os.Stat(arg); err == nil && st.IsDir() {
+			episodes, err := player.EpisodePlaylist(arg)
+			if err != nil {
+				return nil, err
+			}
+			for _, ep := range episodes {
+				queue = append(queue, player.PlaylistEntry{Filename: ep})
+			}
+			continue
+		}
+		if !player.IsPlaylistFile(arg) {
+			queue = append(queue, player.PlaylistEntry{Filename: arg})
+			continue
+		}
+		entries, err := player.ReadM3U(arg)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, entries...)
+	}
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("nenhum arquivo para reproduzir")
+	}
+	return queue, nil
+}
+
+// skipPreferences junta as preferências de pular do player4k.json com a flag -skip
+func skipPreferences(mode string) (player.SkipPreferences, error) {
+	var prefs player.SkipPreferences
+	if cfg, err := player.LoadUserConfig(); err == nil && cfg.Skip != nil {
+		prefs = *cfg.Skip
+	}
+
+	if mode != "" {
+		m, err := player.ParseSkipMode(mode)
+		if err != nil {
+			return prefs, err
+		}
+		// A flag vale para todos os tipos de trecho
+		prefs = player.SkipPreferences{Mode: m}
+	}
+	return prefs, nil
+}
+
+// languagePreferences junta as regras salvas no player4k.json com as flags
+func languagePreferences(alang, slang string) player.LanguagePreferences {
+	var prefs player.LanguagePreferences
+	if cfg, err := player.LoadUserConfig(); err != nil {
+		fmt.Printf("[Player4K] Aviso: %v\n", err)
+	} else if cfg.Languages != nil {
+		prefs = *cfg.Languages
+	}
+
+	if alang != "" {
+		prefs.Audio = player.ParseLanguageList(alang)
+	}
+	if slang != "" {
+		prefs.Subtitles = player.ParseLanguageList(slang)
+	}
+	return prefs
+}
+
+// animeFlag aceita "-anime" (pipeline padrão) ou "-anime=A-HQ"
+type animeFlag struct {
+	preset player.AnimePreset
+}
+
+func (f *animeFlag) String() string {
+	return string(f.preset)
+}
+
+func (f *animeFlag) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1":
+		f.preset = player.DefaultAnimePreset
+		return nil
+	case "false", "no", "0", "":
+		f.preset = ""
+		return nil
+	}
+
+	preset, ok := player.ParseAnimePreset(s)
+	if !ok {
+		return fmt.Errorf("preset Anime4K desconhecido: %s (veja \"player4k modes\")", s)
+	}
+	f.preset = preset
+	return nil
+}
+
+func (f *animeFlag) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runProbe executa "player4k probe [-json] <arquivo>"
+func runProbe(args []string) int {
+	fs := newFlagSet("probe", "probe [-json] <arquivo>", "Mostra faixas, capítulos e resolução sem abrir janela.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+	timeout := fs.Duration("timeout", 15*time.Second, "Tempo máximo para abrir o arquivo")
+
+	files, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(files) != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	result, err := player.Probe(files[0], *timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	if *asJSON {
+		return printJSON(result)
+	}
+
+	fmt.Printf("📄 %s\n", result.Title)
+	fmt.Printf("   Formato: %s  Duração: %s\n", result.Format, formatTime(result.Duration))
+	if result.Width > 0 {
+		fmt.Printf("   Vídeo: %dx%d %.3g fps %s\n", result.Width, result.Height, result.FPS, result.VideoCodec)
+	}
+
+	fmt.Println("\n🎞️  Faixas:")
+	for _, t := range result.Tracks {
+		fmt.Println("  " + describeTrack(t))
+	}
+
+	if len(result.Chapters) > 0 {
+		fmt.Println("\n📑 Capítulos:")
+		for _, c := range result.Chapters {
+			fmt.Printf("  %s  %s\n", formatTime(c.Time), c.Title)
+		}
+	}
+	return exitOK
+}
+
+// describeTrack resume uma faixa numa linha
+func describeTrack(t player.Track) string {
+	parts := []string{fmt.Sprintf("[%s %d]", t.Type, t.ID)}
+	if t.Lang != "" {
+		parts = append(parts, t.Lang)
+	}
+	if t.Codec != "" {
+		parts = append(parts, t.Codec)
+	}
+	if t.Width > 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", t.Width, t.Height))
+	}
+	if t.Channels > 0 {
+		parts = append(parts, fmt.Sprintf("%dch", t.Channels))
+	}
+	if t.Title != "" {
+		parts = append(parts, fmt.Sprintf("%q", t.Title))
+	}
+	if t.Default {
+		parts = append(parts, "(padrão)")
+	}
+	if t.Forced {
+		parts = append(parts, "(forçada)")
+	}
+	if t.External {
+		parts = append(parts, "(externa)")
+	}
+	if t.Selected {
+		parts = append(parts, "*")
+	}
+	return strings.Join(parts, " ")
+}
+
+// printJSON escreve v indentado na saída padrão
+func printJSON(v interface{}) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runProfiles executa "player4k profiles list|export|reset"
+func runProfiles(args []string) int {
+	if len(args) == 0 {
+		printProfilesUsage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return runProfilesList(args[1:])
+	case "export":
+		return runProfilesExport(args[1:])
+	case "reset":
+		return runProfilesReset(args[1:])
+	case "-h", "-help", "--help":
+		printProfilesUsage()
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Subcomando desconhecido: profiles %s\n", args[0])
+	printProfilesUsage()
+	return exitUsage
+}
+
+func printProfilesUsage() {
+	fmt.Println(`📖 USO: player4k profiles <list|export|reset>
+
+   list     Listar os perfis por série (modo, áudio, atraso da legenda, volume)
+   export   Gravar os perfis em JSON (arquivo ou saída padrão)
+   reset    Apagar o perfil de uma série (ou todos com -all)`)
+}
+
+// openProfiles abre o profiles.json da pasta de configuração
+func openProfiles() (*player.ProfileStore, bool) {
+	store, err := player.OpenDefaultProfileStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return nil, false
+	}
+	return store, true
+}
+
+// runProfilesList executa "player4k profiles list"
+func runProfilesList(args []string) int {
+	fs := newFlagSet("profiles list", "profiles list [-json]",
+		"Lista os ajustes lembrados de cada série.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	store, ok := openProfiles()
+	if !ok {
+		return exitError
+	}
+	list := store.List()
+	if *asJSON {
+		return printJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Println("Nenhum perfil salvo (os ajustes feitos durante um episódio criam o perfil da série)")
+		return exitOK
+	}
+	for _, sp := range list {
+		fmt.Printf("📺 %s  %s\n", sp.Series, sp.Summary())
+	}
+	return exitOK
+}
+
+// runProfilesExport executa "player4k profiles export [arquivo]"
+func runProfilesExport(args []string) int {
+	fs := newFlagSet("profiles export", "profiles export [arquivo.json]",
+		"Grava os perfis em JSON, no mesmo formato do profiles.json. Sem arquivo, escreve na saída padrão.")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	store, ok := openProfiles()
+	if !ok {
+		return exitError
+	}
+	if len(rest) == 0 {
+		if err := store.Export(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	f, err := os.Create(rest[0])
+	if err == nil {
+		err = store.Export(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✓ %d perfis exportados para %s\n", len(store.List()), rest[0])
+	return exitOK
+}
+
+// runProfilesReset executa "player4k profiles reset <série>" ou "profiles reset -all"
+func runProfilesReset(args []string) int {
+	fs := newFlagSet("profiles reset", "profiles reset [-all] [série]",
+		"Apaga o perfil de uma série; o próximo episódio volta às opções padrão.")
+	all := fs.Bool("all", false, "Apagar todos os perfis")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if *all == (len(rest) == 1) || len(rest) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	store, ok := openProfiles()
+	if !ok {
+		return exitError
+	}
+	if *all {
+		n := store.ResetAll()
+		if err := store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+		fmt.Printf("✓ %d perfis apagados\n", n)
+		return exitOK
+	}
+
+	if !store.Reset(rest[0]) {
+		fmt.Fprintf(os.Stderr, "❌ Nenhum perfil para %q (veja \"player4k profiles list\")\n", rest[0])
+		return exitError
+	}
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✓ Perfil de %s apagado\n", rest[0])
+	return exitOK
+}
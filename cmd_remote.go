@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// remoteCommand é um comando aceito por "player4k remote"
+type remoteCommand struct {
+	name    string
+	args    string // descrição dos argumentos para a ajuda
+	nargs   int
+	summary string
+	run     func(c *player.IPCClient, args []string) (interface{}, error)
+}
+
+var remoteCommands = []remoteCommand{
+	{"status", "", 0, "Arquivo, posição, duração, pausa e volume", remoteStatus},
+	{"pause", "", 0, "Pausar", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		return nil, c.SetProperty("pause", true)
+	}},
+	{"resume", "", 0, "Retomar", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		return nil, c.SetProperty("pause", false)
+	}},
+	{"toggle", "", 0, "Alternar play/pause", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		_, err := c.Command("cycle", "pause")
+		return nil, err
+	}},
+	{"seek", "<±segundos>", 1, "Avançar/voltar relativo à posição atual", func(c *player.IPCClient, args []string) (interface{}, error) {
+		_, err := c.Command("seek", args[0], "relative")
+		return nil, err
+	}},
+	{"seek-to", "<segundos>", 1, "Ir para uma posição absoluta", func(c *player.IPCClient, args []string) (interface{}, error) {
+		_, err := c.Command("seek", args[0], "absolute")
+		return nil, err
+	}},
+	{"volume", "<0-150>", 1, "Definir volume", func(c *player.IPCClient, args []string) (interface{}, error) {
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 0 || v > 150 {
+			return nil, fmt.Errorf("volume inválido: %s", args[0])
+		}
+		return nil, c.SetProperty("volume", v)
+	}},
+	{"load", "<arquivo|url>", 1, "Carregar outro arquivo", func(c *player.IPCClient, args []string) (interface{}, error) {
+		_, err := c.Command("loadfile", args[0])
+		return nil, err
+	}},
+	{"fullscreen", "", 0, "Alternar tela cheia", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		_, err := c.Command("cycle", "fullscreen")
+		return nil, err
+	}},
+	{"screenshot", "", 0, "Capturar a tela", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		_, err := c.Command("screenshot")
+		return nil, err
+	}},
+	{"stop", "", 0, "Parar a reprodução", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		_, err := c.Command("stop")
+		return nil, err
+	}},
+	{"quit", "", 0, "Fechar o player", func(c *player.IPCClient, _ []string) (interface{}, error) {
+		_, err := c.Command("quit")
+		return nil, err
+	}},
+	{"get", "<propriedade>", 1, "Ler uma propriedade do MPV", func(c *player.IPCClient, args []string) (interface{}, error) {
+		return c.GetProperty(args[0])
+	}},
+	{"set", "<propriedade> <valor>", 2, "Alterar uma propriedade do MPV", func(c *player.IPCClient, args []string) (interface{}, error) {
+		_, err := c.Command("set", args[0], args[1])
+		return nil, err
+	}},
+}
+
+// runRemote executa "player4k remote [-ipc=caminho] <comando> [args]"
+func runRemote(args []string) int {
+	fs := newFlagSet("remote", "remote [-ipc=caminho] [-json] <comando> [argumentos]", remoteHelp())
+	ipcPath := fs.String("ipc", player.DefaultIPCPath(), "Socket/pipe da instância (o mesmo de \"play -ipc\")")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	// Sem intercalar flags: "seek -10" precisa chegar como argumento
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	name, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	var cmd *remoteCommand
+	for i := range remoteCommands {
+		if remoteCommands[i].name == name {
+			cmd = &remoteCommands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "❌ Comando remoto desconhecido: %s\n", name)
+		return exitUsage
+	}
+	if len(cmdArgs) != cmd.nargs {
+		fmt.Fprintf(os.Stderr, "📖 USO: player4k remote %s %s\n", cmd.name, cmd.args)
+		return exitUsage
+	}
+
+	client, err := player.DialIPC(*ipcPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Nenhuma instância do player em %s: %v\n", *ipcPath, err)
+		return exitUnavailable
+	}
+	defer client.Close()
+
+	out, err := cmd.run(client, cmdArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	switch v := out.(type) {
+	case nil:
+		if *asJSON {
+			return printJSON(map[string]bool{"ok": true})
+		}
+	case remoteStatusInfo:
+		if *asJSON {
+			return printJSON(v)
+		}
+		state := "▶ reproduzindo"
+		if v.Paused {
+			state = "⏸ pausado"
+		}
+		fmt.Printf("%s %s\n   %s / %s  •  volume %d%%\n", state, v.Title, formatTime(v.Position), formatTime(v.Duration), v.Volume)
+	case json.RawMessage:
+		fmt.Println(string(v))
+	}
+	return exitOK
+}
+
+// remoteHelp monta a lista de comandos remotos para a ajuda
+func remoteHelp() string {
+	var b strings.Builder
+	b.WriteString("Controla uma instância iniciada com \"player4k play\". Comandos:\n\n")
+	for _, cmd := range remoteCommands {
+		fmt.Fprintf(&b, "   %-32s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// remoteStatusInfo é a saída de "remote status"
+type remoteStatusInfo struct {
+	Title    string  `json:"title"`
+	Path     string  `json:"path"`
+	Position float64 `json:"position"`
+	Duration float64 `json:"duration"`
+	Paused   bool    `json:"paused"`
+	Volume   int     `json:"volume"`
+}
+
+// remoteStatus lê as propriedades principais; as indisponíveis (sem arquivo) ficam zeradas
+func remoteStatus(c *player.IPCClient, _ []string) (interface{}, error) {
+	var info remoteStatusInfo
+	var volume float64
+	fields := []struct {
+		name string
+		dst  interface{}
+	}{
+		{"media-title", &info.Title},
+		{"path", &info.Path},
+		{"time-pos", &info.Position},
+		{"duration", &info.Duration},
+		{"pause", &info.Paused},
+		{"volume", &volume},
+	}
+	for _, f := range fields {
+		data, err := c.GetProperty(f.name)
+		if err != nil {
+			continue
+		}
+		json.Unmarshal(data, f.dst)
+	}
+	info.Volume = int(volume)
+	return info, nil
+}
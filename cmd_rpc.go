@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runRPC executa "player4k rpc <Método> [parâmetros]" contra o servidor JSON-RPC
+// Cada parâmetro é lido como JSON; o que não for JSON válido vai como texto
+func runRPC(args []string) int {
+	fs := newFlagSet("rpc", "rpc [-rpc=caminho] [-watch] <Método> [parâmetros...]",
+		"Chama um método do WailsPlayer numa instância iniciada com \"player4k serve\".\n"+
+			"Ex: player4k rpc SetQualityMode high | player4k rpc Seek 90 | player4k rpc rpc.methods")
+	rpcPath := fs.String("rpc", player.DefaultRPCPath(), "Socket do servidor JSON-RPC")
+	watch := fs.Bool("watch", false, "Depois da chamada, mostrar as notificações até a conexão fechar")
+
+	// Sem intercalar flags: parâmetros negativos ("Seek -10") não são flags
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 && !*watch {
+		fs.Usage()
+		return exitUsage
+	}
+
+	conn, err := net.DialTimeout("unix", *rpcPath, 3*time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Nenhum servidor JSON-RPC em %s: %v\n", *rpcPath, err)
+		return exitUnavailable
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	if fs.NArg() > 0 {
+		params := make([]json.RawMessage, 0, fs.NArg()-1)
+		for _, arg := range fs.Args()[1:] {
+			if json.Valid([]byte(arg)) {
+				params = append(params, json.RawMessage(arg))
+			} else {
+				quoted, _ := json.Marshal(arg)
+				params = append(params, quoted)
+			}
+		}
+
+		req, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1, "method": fs.Arg(0), "params": params,
+		})
+		if _, err := conn.Write(append(req, '\n')); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+
+		if code := readRPCResponse(reader); code != exitOK || !*watch {
+			return code
+		}
+	}
+
+	// -watch: uma notificação JSON por linha
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return exitOK
+		}
+		fmt.Print(string(line))
+	}
+}
+
+// readRPCResponse lê até a resposta da chamada (ignora notificações) e a imprime
+func readRPCResponse(reader *bufio.Reader) int {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Conexão encerrada: %v\n", err)
+			return exitError
+		}
+
+		var resp struct {
+			ID     json.RawMessage  `json:"id"`
+			Result json.RawMessage  `json:"result"`
+			Error  *player.RPCError `json:"error"`
+		}
+		if json.Unmarshal(line, &resp) != nil || resp.ID == nil {
+			continue
+		}
+		if resp.Error != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s (código %d)\n", resp.Error.Message, resp.Error.Code)
+			return exitError
+		}
+		fmt.Println(string(resp.Result))
+		return exitOK
+	}
+}
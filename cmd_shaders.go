@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+)
+
+// runShaders executa "player4k shaders verify|list [pasta]"
+func runShaders(args []string) int {
+	if len(args) == 0 {
+		printShadersUsage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "verify":
+		return runShadersVerify(args[1:])
+	case "list":
+		return runShadersList(args[1:])
+	case "-h", "-help", "--help":
+		printShadersUsage()
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Subcomando desconhecido: shaders %s\n", args[0])
+	printShadersUsage()
+	return exitUsage
+}
+
+func printShadersUsage() {
+	fmt.Println(`📖 USO: player4k shaders <verify|list> [-json] [pasta_de_shaders]
+
+   verify   Verificar existência e SHA-256 (sai com 1 se algum obrigatório falhar)
+   list     Listar os shaders do manifesto com custo, licença e situação`)
+}
+
+// shaderDirArg devolve a pasta informada ou a padrão
+func shaderDirArg(rest []string) string {
+	if len(rest) > 0 {
+		return rest[0]
+	}
+	return player.DefaultShaderDir()
+}
+
+// runShadersVerify executa "player4k shaders verify [pasta]"
+// Retorna 0 se todos os shaders obrigatórios estão íntegros
+func runShadersVerify(args []string) int {
+	fs := newFlagSet("shaders verify", "shaders verify [-json] [pasta_de_shaders]",
+		"Confere cada shader do manifesto (existência e SHA-256).")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	report, err := player.VerifyShaderDir(shaderDirArg(rest))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	if *asJSON {
+		if code := printJSON(report); code != exitOK {
+			return code
+		}
+	} else {
+		fmt.Printf("🔍 Verificando shaders em %s\n\n", report.Dir)
+		for _, c := range report.Checks {
+			switch c.Status {
+			case player.ShaderOK:
+				fmt.Printf("  ✓ %s\n", c.Path)
+			case player.ShaderUnlisted:
+				fmt.Printf("  ? %s (fora do manifesto)\n", c.Path)
+			default:
+				line := fmt.Sprintf("  ✗ %s (%s)", c.Path, c.Status)
+				if c.Optional {
+					line += " [opcional]"
+				}
+				if c.Fallback != "" {
+					line += " → fallback: " + c.Fallback
+				}
+				fmt.Println(line)
+			}
+		}
+		fmt.Printf("\n%d ok, %d faltando, %d corrompidos\n", report.OK, report.Missing, report.Corrupt)
+	}
+
+	if !report.Healthy() {
+		return exitError
+	}
+	return exitOK
+}
+
+// runShadersList executa "player4k shaders list [pasta]"
+func runShadersList(args []string) int {
+	fs := newFlagSet("shaders list", "shaders list [-json] [pasta_de_shaders]",
+		"Lista os shaders do manifesto com custo, licença e situação na pasta.")
+	asJSON := fs.Bool("json", false, "Saída em JSON")
+
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	dir := shaderDirArg(rest)
+	manifest, err := player.LoadShaderManifest(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	status := make(map[string]player.ShaderStatus)
+	for _, c := range manifest.Verify(dir).Checks {
+		status[c.Name] = c.Status
+	}
+
+	if *asJSON {
+		type entry struct {
+			player.ShaderEntry
+			Status player.ShaderStatus `json:"status"`
+		}
+		list := make([]entry, len(manifest.Shaders))
+		for i, e := range manifest.Shaders {
+			list[i] = entry{e, status[e.Name]}
+		}
+		return printJSON(list)
+	}
+
+	fmt.Printf("📦 Shaders do manifesto (%s)\n\n", dir)
+	for _, e := range manifest.Shaders {
+		icon := "✓"
+		if status[e.Name] != player.ShaderOK {
+			icon = "✗"
+		}
+		fmt.Printf("  %s %-40s %-7s %-12s %s\n", icon, e.Name, e.Cost, e.License, e.Path)
+	}
+	return exitOK
+}
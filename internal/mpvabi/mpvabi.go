@@ -0,0 +1,41 @@
+// Package mpvabi lê estruturas do libmpv que o go-mpv não decodifica
+// Não importa o go-mpv, então pode ser testado sem o libmpv instalado
+package mpvabi
+
+import "unsafe"
+
+// clientMessage espelha mpv_event_client_message
+type clientMessage struct {
+	NumArgs int32
+	Args    unsafe.Pointer // const char **
+}
+
+// ClientMessageArgs copia os argumentos de um mpv_event_client_message para strings do Go
+func ClientMessageArgs(data unsafe.Pointer) []string {
+	if data == nil {
+		return []string{}
+	}
+	msg := (*clientMessage)(data)
+	if msg.NumArgs <= 0 || msg.Args == nil {
+		return []string{}
+	}
+
+	ptrs := unsafe.Slice((*unsafe.Pointer)(msg.Args), msg.NumArgs)
+	args := make([]string, len(ptrs))
+	for i, ptr := range ptrs {
+		args[i] = goString(ptr)
+	}
+	return args
+}
+
+// goString copia uma string C terminada em zero
+func goString(ptr unsafe.Pointer) string {
+	if ptr == nil {
+		return ""
+	}
+	n := 0
+	for *(*byte)(unsafe.Add(ptr, n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice((*byte)(ptr), n))
+}
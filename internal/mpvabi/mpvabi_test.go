@@ -0,0 +1,50 @@
+package mpvabi
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// cStrings monta um const char ** com strings terminadas em zero, como o libmpv entrega
+func cStrings(args []string) ([][]byte, []unsafe.Pointer) {
+	bufs := make([][]byte, len(args))
+	ptrs := make([]unsafe.Pointer, len(args))
+	for i, a := range args {
+		bufs[i] = append([]byte(a), 0)
+		ptrs[i] = unsafe.Pointer(&bufs[i][0])
+	}
+	return bufs, ptrs
+}
+
+func TestClientMessageArgs(t *testing.T) {
+	tests := [][]string{
+		{"player4k", "switch-mode", "high"},
+		{"player4k", "mark-watched"},
+		{"goanime-toast", "✓ Marcado como assistido", "2"},
+		{"uosc", ""},
+	}
+
+	for _, want := range tests {
+		bufs, ptrs := cStrings(want)
+		msg := clientMessage{NumArgs: int32(len(ptrs)), Args: unsafe.Pointer(&ptrs[0])}
+
+		got := ClientMessageArgs(unsafe.Pointer(&msg))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ClientMessageArgs\n got: %q\nwant: %q", got, want)
+		}
+		runtime.KeepAlive(bufs)
+	}
+}
+
+func TestClientMessageArgsEmpty(t *testing.T) {
+	if got := ClientMessageArgs(nil); len(got) != 0 || got == nil {
+		t.Errorf("ClientMessageArgs(nil) = %#v, want empty slice", got)
+	}
+
+	msg := clientMessage{}
+	if got := ClientMessageArgs(unsafe.Pointer(&msg)); len(got) != 0 || got == nil {
+		t.Errorf("sem argumentos = %#v, want empty slice", got)
+	}
+}
@@ -1,130 +1,137 @@
 // Player4K - Player de vídeo com upscaling AI para GoAnimeGUI
 // Usa MPV como backend com shaders GLSL para upscaling de alta qualidade
-//go:build windows
-// +build windows
-
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
-	"player4k/player"
+	_ "github.com/ThiagoFrag/Goanime-Player4k/player/mpvengine" // backend libmpv
 )
 
-func main() {
-	// Flags de linha de comando
-	modeFlag := flag.String("mode", "medium", "Modo de qualidade: low, medium, high")
-	animeFlag := flag.Bool("anime", false, "Ativar modo otimizado para anime (Anime4K)")
-	titleFlag := flag.String("title", "", "Título para exibir na janela")
-	subFlag := flag.String("sub", "", "URL ou caminho de legenda externa")
-	listModes := flag.Bool("list-modes", false, "Listar todos os modos disponíveis")
-	fullscreen := flag.Bool("fs", false, "Iniciar em tela cheia")
-	volume := flag.Int("volume", 100, "Volume inicial (0-150)")
-	startPos := flag.Float64("start", 0, "Posição inicial em segundos")
-	flag.Parse()
-
-	if *listModes {
-		printBanner()
-		fmt.Println("\n🎬 Modos de Qualidade Disponíveis:")
-		fmt.Println("─────────────────────────────────────")
-		for _, mode := range player.GetAllModes() {
-			fmt.Printf("\n  %s %s (%s)\n", mode.Icon, mode.Name, mode.ID)
-			fmt.Printf("     📝 %s\n", mode.Description)
-			fmt.Printf("     🎮 GPU: %s\n", mode.GPURequired)
-		}
-		fmt.Println("\n─────────────────────────────────────")
-		printControls()
-		return
-	}
-
-	// Criar instância do player
-	p, err := player.New()
-	if err != nil {
-		os.Exit(1)
-	}
-	defer p.Destroy()
+// Códigos de saída de todos os subcomandos (usados pelo GoAnimeGUI e scripts)
+const (
+	exitOK          = 0 // sucesso
+	exitError       = 1 // falha ao executar (arquivo inválido, shaders corrompidos...)
+	exitUsage       = 2 // argumentos inválidos ou subcomando desconhecido
+	exitUnavailable = 3 // nenhuma instância em execução (remote)
+)
 
-	// Carregar arquivos de configuração
-	execPath, _ := os.Executable()
-	execDir := filepath.Dir(execPath)
+// command é um subcomando da CLI
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
 
-	// Carregar atalhos customizados (input.conf)
-	inputConf := filepath.Join(execDir, "input.conf")
-	if _, err := os.Stat(inputConf); err == nil {
-		p.LoadInputConfig(inputConf)
+// commands é preenchido no init para que "help" possa listar todos
+var commands []command
+
+func init() {
+	commands = []command{
+		{"play", "Reproduzir um arquivo ou URL (padrão quando nenhum comando é informado)", runPlay},
+		{"serve", "Reproduzir controlado pelo GoAnimeGUI via JSON-RPC (socket local)", runServe},
+		{"rpc", "Chamar um método JSON-RPC de uma instância (player4k rpc GetStats)", runRPC},
+		{"probe", "Mostrar faixas, capítulos e resolução de um arquivo", runProbe},
+		{"analyze-intro", "Encontrar abertura e encerramento comparando o áudio dos episódios de uma pasta", runAnalyzeIntro},
+		{"profiles", "Listar, exportar ou apagar os perfis por série (list | export | reset)", runProfiles},
+		{"config", "Mostrar a configuração em camadas e a pasta do MPV (show [--effective] | bundle | path)", runConfig},
+		{"modes", "Listar modos de qualidade e presets Anime4K", runModes},
+		{"shaders", "Verificar ou listar os shaders (verify | list)", runShaders},
+		{"bench", "Medir a GPU e salvar o modo recomendado", runBench},
+		{"remote", "Controlar uma instância em execução", runRemote},
+		{"help", "Ajuda de um comando (player4k help <comando>)", runHelp},
 	}
+}
 
-	// Carregar script OSC (barra de controles na tela)
-	oscScript := filepath.Join(execDir, "scripts", "osc.lua")
-	if _, err := os.Stat(oscScript); err == nil {
-		p.LoadScript(oscScript)
-	}
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
 
-	// Configurar modo de qualidade
-	var mode player.PerformanceMode
-	switch *modeFlag {
-	case "low":
-		mode = player.ModeLow
-	case "high":
-		mode = player.ModeHigh
-	default:
-		mode = player.ModeMedium
+// run escolhe o subcomando e devolve o código de saída
+func run(args []string) int {
+	if len(args) == 0 {
+		printBanner()
+		printUsage()
+		return exitUsage
 	}
-	p.SetPerformanceMode(mode)
 
-	// Ativar modo anime se solicitado
-	if *animeFlag {
-		p.SetAnimeMode(true)
+	switch args[0] {
+	case "-h", "-help", "--help":
+		printUsage()
+		return exitOK
+	case "-list-modes", "--list-modes":
+		// Compatibilidade com a flag antiga
+		return runModes(args[1:])
 	}
 
-	// Configurar volume
-	if *volume != 100 {
-		p.SetVolume(*volume)
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
 	}
 
-	// Carregar vídeo
-	args := flag.Args()
-	if len(args) > 0 {
-		videoPath := args[0]
-
-		// Define título da janela
-		windowTitle := *titleFlag
-		if windowTitle == "" {
-			windowTitle = "▶ " + filepath.Base(videoPath) + " - GoAnime Player"
-		}
-		p.SetTitle(windowTitle)
+	// Sem subcomando: "player4k [opções] arquivo" continua funcionando como play
+	return runPlay(args)
+}
 
-		// Fullscreen
-		if *fullscreen {
-			p.SetFullscreen(true)
+// runHelp executa "player4k help [comando]"
+func runHelp(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] && cmd.name != "help" {
+			return cmd.run([]string{"-h"})
 		}
+	}
+	fmt.Fprintf(os.Stderr, "❌ Comando desconhecido: %s\n", args[0])
+	return exitUsage
+}
 
-		if err := p.LoadFile(videoPath); err != nil {
-			os.Exit(1)
+// newFlagSet cria as flags de um subcomando com ajuda própria
+func newFlagSet(name, usage, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "📖 USO: player4k %s\n\n%s\n", usage, description)
+
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(fs.Output(), "\n🎛️  OPÇÕES:")
+			fs.PrintDefaults()
 		}
+	}
+	return fs
+}
 
-		// Carregar legenda externa se fornecida
-		if *subFlag != "" {
-			if err := p.LoadSubtitle(*subFlag); err != nil {
-				fmt.Printf("[Player4K] Aviso: não foi possível carregar legenda: %v\n", err)
+// parseFlags interpreta as flags; quando ok é false, code é o código de saída
+// Flags podem vir depois dos argumentos (ex: "probe ep01.mkv -json")
+func parseFlags(fs *flag.FlagSet, args []string) (positional []string, code int, ok bool) {
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, exitOK, false
 			}
+			return nil, exitUsage, false
 		}
-
-		// Posição inicial
-		if *startPos > 0 {
-			p.Seek(*startPos)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, exitOK, true
 		}
-	} else {
-		printBanner()
-		printUsage()
-		return
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
+}
 
-	// Loop de eventos
-	p.Run()
+// formatTime formata segundos como hh:mm:ss
+func formatTime(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
 }
 
 func printBanner() {
@@ -140,18 +147,21 @@ func printBanner() {
 }
 
 func printUsage() {
-	fmt.Println(`
-📖 USO: player4k [opções] <arquivo_de_video>
-
-🎛️  OPÇÕES:
-   -mode=low|medium|high    Modo de qualidade (padrão: medium)
-   -anime                   Ativar shaders Anime4K otimizados
-   -title="Título"          Título personalizado da janela
-   -sub="URL ou caminho"    Carregar legenda externa
-   -fs                      Iniciar em tela cheia
-   -volume=0-150            Volume inicial
-   -start=SEGUNDOS          Posição inicial
-   -list-modes              Ver modos disponíveis`)
+	var b strings.Builder
+	b.WriteString("\n📖 USO: player4k <comando> [opções]\n\n🧰 COMANDOS:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "   %-10s %s\n", cmd.name, cmd.summary)
+	}
+	b.WriteString(`
+   player4k [opções] <arquivo> equivale a "player4k play".
+   Use "player4k help <comando>" para ver as opções de cada comando.
+
+🚦 CÓDIGOS DE SAÍDA:
+   0  Sucesso
+   1  Falha ao executar
+   2  Uso incorreto
+   3  Nenhuma instância em execução (remote)`)
+	fmt.Println(b.String())
 }
 
 func printControls() {
@@ -160,7 +170,7 @@ func printControls() {
    ESPAÇO        Play/Pause
    ← →           Seek -5s/+5s
    ↑ ↓           Volume +/-
-   I             Pular intro (85s)
+   I             Pular abertura/encerramento
    F             Tela cheia
    S             Screenshot
    M             Mute
@@ -18,7 +18,6 @@ local config = {
     show_logo_on_start = true,
     logo_duration = 2.0,
     show_filename_on_load = true,
-    auto_skip_intro = false,
     custom_osd = true
 }
 
@@ -166,6 +165,127 @@ mp.add_key_binding("ctrl+i", "goanime-info", show_video_info)
 -- Mostrar logo novamente
 mp.add_key_binding("ctrl+l", "goanime-logo", show_goanime_logo)
 
+-- ═══════════════════════════════════════════════════════════════════════════════
+--  PONTE COM O PLAYER 4K (script-message)
+--  Go → Lua: script-message-to goanime <comando> ...
+--  Lua → Go: script-message player4k <pedido> ...
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+local toast_overlay = mp.create_osd_overlay("ass-events")
+local skip_overlay = mp.create_osd_overlay("ass-events")
+local banner_overlay = mp.create_osd_overlay("ass-events")
+local toast_timer = nil
+local banner_timer = nil
+
+-- Escapa texto para ASS (chaves e quebras de linha)
+local function ass_escape(text)
+    text = string.gsub(text or "", "\\", "\\\\")
+    text = string.gsub(text, "{", "\\{")
+    text = string.gsub(text, "}", "\\}")
+    text = string.gsub(text, "\n", "\\N")
+    return text
+end
+
+local function osd_size()
+    local osd_w, osd_h = mp.get_osd_size()
+    if not osd_w or osd_w == 0 then
+        osd_w, osd_h = 1920, 1080
+    end
+    return osd_w, osd_h
+end
+
+-- Caixa com fundo escuro e borda rosa
+local function draw_box(ass, x, y, w, h)
+    ass:new_event()
+    ass:pos(0, 0)
+    ass:append("{\\an7\\bord2\\shad0\\1c&H" .. DARK_BG .. "&\\3c&H" .. PINK .. "&\\alpha&H30&}")
+    ass:draw_start()
+    ass:round_rect_cw(x, y, x + w, y + h, 8)
+    ass:draw_stop()
+end
+
+local function show_overlay(overlay, ass)
+    overlay.res_x, overlay.res_y = osd_size()
+    overlay.data = ass.text
+    overlay:update()
+end
+
+-- Envia um pedido ao player (chega no Go como ScriptRequestEvent)
+local function request(name, ...)
+    mp.commandv("script-message", "player4k", name, ...)
+end
+
+-- goanime-toast <texto> <segundos>
+mp.register_script_message("goanime-toast", function(text, seconds)
+    local osd_w, osd_h = osd_size()
+    local ass = assdraw.ass_new()
+    draw_box(ass, osd_w / 2 - 300, 40, 600, 56)
+    ass:new_event()
+    ass:pos(osd_w / 2, 68)
+    ass:an(5)
+    ass:append("{\\fn Segoe UI\\fs26\\bord0\\shad0\\1c&H" .. WHITE .. "&}")
+    ass:append(ass_escape(text))
+    show_overlay(toast_overlay, ass)
+
+    if toast_timer then toast_timer:kill() end
+    toast_timer = mp.add_timeout(tonumber(seconds) or 2, function()
+        toast_overlay:remove()
+    end)
+end)
+
+-- goanime-skip-button <tipo> <rótulo> <tecla> <fim>
+mp.register_script_message("goanime-skip-button", function(kind, label, key, ending)
+    local osd_w, osd_h = osd_size()
+    local ass = assdraw.ass_new()
+    draw_box(ass, osd_w - 340, osd_h - 200, 300, 64)
+    ass:new_event()
+    ass:pos(osd_w - 190, osd_h - 168)
+    ass:an(5)
+    ass:append("{\\fn Segoe UI\\fs30\\b1\\bord0\\shad0\\1c&H" .. WHITE .. "&}")
+    ass:append("⏭️ " .. ass_escape(label))
+    ass:append("{\\fs22\\b0\\1c&H" .. PINK .. "&}  [" .. ass_escape(key) .. "]")
+    show_overlay(skip_overlay, ass)
+    msg.verbose("Botão de pular: " .. (kind or "") .. " até " .. (ending or "?"))
+end)
+
+-- goanime-skip-hide
+mp.register_script_message("goanime-skip-hide", function()
+    skip_overlay:remove()
+end)
+
+-- goanime-mode-banner <modo> <nome> <ícone> <motivo>
+mp.register_script_message("goanime-mode-banner", function(mode, name, icon, reason)
+    local osd_w, osd_h = osd_size()
+    local ass = assdraw.ass_new()
+    draw_box(ass, 40, 40, 520, 84)
+    ass:new_event()
+    ass:pos(64, 58)
+    ass:an(7)
+    ass:append("{\\fn Segoe UI\\fs30\\b1\\bord0\\shad0\\1c&H" .. PINK .. "&}")
+    ass:append(ass_escape((icon or "") .. " " .. (name or mode or "")))
+    if reason and reason ~= "" then
+        ass:new_event()
+        ass:pos(64, 96)
+        ass:an(7)
+        ass:append("{\\fn Segoe UI\\fs18\\b0\\bord0\\shad0\\1c&H" .. WHITE .. "&}")
+        ass:append(ass_escape(reason))
+    end
+    show_overlay(banner_overlay, ass)
+
+    if banner_timer then banner_timer:kill() end
+    banner_timer = mp.add_timeout(3, function()
+        banner_overlay:remove()
+    end)
+end)
+
+-- Pedidos ao player (use com script-binding goanime/<nome> no input.conf)
+mp.add_key_binding(nil, "mode-low", function() request("switch-mode", "low") end)
+mp.add_key_binding(nil, "mode-medium", function() request("switch-mode", "medium") end)
+mp.add_key_binding(nil, "mode-high", function() request("switch-mode", "high") end)
+mp.add_key_binding(nil, "mark-watched", function() request("mark-watched") end)
+mp.add_key_binding(nil, "next-episode", function() request("next-episode") end)
+mp.add_key_binding(nil, "skip-segment", function() request("skip-segment") end)
+
 -- ═══════════════════════════════════════════════════════════════════════════════
 --  EVENTOS
 -- ═══════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,280 @@
+package player
+
+import (
+	"fmt"
+	"time"
+)
+
+// FrameSample é uma leitura dos contadores de frames do MPV
+type FrameSample struct {
+	At      time.Time
+	Dropped int64 // frame-drop-count (descartados pelo VO)
+	Decoder int64 // decoder-frame-drop-count
+	Delayed int64 // vo-delayed-frame-count
+}
+
+// total soma todos os contadores de problema
+func (s FrameSample) total() int64 {
+	return s.Dropped + s.Decoder + s.Delayed
+}
+
+// AdaptiveConfig controla o ajuste automático de qualidade
+type AdaptiveConfig struct {
+	Interval      time.Duration     // intervalo entre amostras
+	Window        time.Duration     // janela deslizante usada para calcular a taxa
+	DowngradeRate float64           // frames problemáticos/s que forçam descer um nível
+	UpgradeRate   float64           // abaixo disso a reprodução é considerada estável
+	StableFor     time.Duration     // tempo estável antes de subir um nível
+	Cooldown      time.Duration     // tempo mínimo entre duas mudanças
+	AllowUpgrade  bool              // permite voltar a subir depois de estabilizar
+	Ladder        []PerformanceMode // níveis possíveis, do mais leve ao mais pesado
+}
+
+// DefaultAdaptiveConfig retorna a configuração padrão do controlador
+func DefaultAdaptiveConfig() AdaptiveConfig {
+	return AdaptiveConfig{
+		Interval:      time.Second,
+		Window:        10 * time.Second,
+		DowngradeRate: 2.0,
+		UpgradeRate:   0.1,
+		StableFor:     2 * time.Minute,
+		Cooldown:      15 * time.Second,
+		AllowUpgrade:  true,
+		Ladder:        []PerformanceMode{ModeLow, ModeMedium, ModeHigh},
+	}
+}
+
+// AdaptiveDecision explica uma mudança automática de modo
+type AdaptiveDecision struct {
+	From      PerformanceMode `json:"from"`
+	To        PerformanceMode `json:"to"`
+	Direction string          `json:"direction"` // "down" ou "up"
+	Rate      float64         `json:"rate"`      // frames problemáticos por segundo na janela
+	Reason    string          `json:"reason"`
+}
+
+// AdaptiveController decide quando descer ou subir o modo de qualidade
+// É puro (não fala com o MPV): recebe amostras e devolve decisões
+type AdaptiveController struct {
+	cfg         AdaptiveConfig
+	samples     []FrameSample
+	lastChange  time.Time
+	stableSince time.Time
+	ceiling     PerformanceMode // modo escolhido pelo usuário; nunca sobe acima dele
+}
+
+// NewAdaptiveController cria um controlador com a configuração informada
+func NewAdaptiveController(cfg AdaptiveConfig, ceiling PerformanceMode) *AdaptiveController {
+	if len(cfg.Ladder) == 0 {
+		cfg.Ladder = DefaultAdaptiveConfig().Ladder
+	}
+	return &AdaptiveController{cfg: cfg, ceiling: ceiling}
+}
+
+// SetCeiling define o modo máximo (normalmente o escolhido pelo usuário)
+func (c *AdaptiveController) SetCeiling(mode PerformanceMode) {
+	c.ceiling = mode
+	c.Reset()
+}
+
+// Reset descarta as amostras (ex: ao trocar de arquivo)
+func (c *AdaptiveController) Reset() {
+	c.samples = nil
+	c.stableSince = time.Time{}
+}
+
+// Rate retorna a taxa atual de frames problemáticos por segundo
+func (c *AdaptiveController) Rate() float64 {
+	if len(c.samples) < 2 {
+		return 0
+	}
+	first, last := c.samples[0], c.samples[len(c.samples)-1]
+	elapsed := last.At.Sub(first.At).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.total()-first.total()) / elapsed
+}
+
+// Observe registra uma amostra e retorna uma decisão (nil se nada mudar)
+func (c *AdaptiveController) Observe(s FrameSample, current PerformanceMode) *AdaptiveDecision {
+	// Contadores zeram quando um novo arquivo começa
+	if n := len(c.samples); n > 0 && s.total() < c.samples[n-1].total() {
+		c.Reset()
+	}
+
+	c.samples = append(c.samples, s)
+
+	// Descartar amostras fora da janela
+	cut := 0
+	for cut < len(c.samples)-1 && s.At.Sub(c.samples[cut].At) > c.cfg.Window {
+		cut++
+	}
+	c.samples = c.samples[cut:]
+
+	// Só decide com pelo menos meia janela de dados
+	if s.At.Sub(c.samples[0].At) < c.cfg.Window/2 {
+		return nil
+	}
+
+	rate := c.Rate()
+
+	if rate < c.cfg.UpgradeRate {
+		if c.stableSince.IsZero() {
+			c.stableSince = s.At
+		}
+	} else {
+		c.stableSince = time.Time{}
+	}
+
+	// Histerese: nenhuma mudança durante o cooldown
+	if !c.lastChange.IsZero() && s.At.Sub(c.lastChange) < c.cfg.Cooldown {
+		return nil
+	}
+
+	if rate >= c.cfg.DowngradeRate {
+		to, ok := c.stepDown(current)
+		if !ok {
+			return nil
+		}
+		return c.decide(s.At, &AdaptiveDecision{
+			From:      current,
+			To:        to,
+			Direction: "down",
+			Rate:      rate,
+			Reason: fmt.Sprintf("%.1f frames perdidos/atrasados por segundo nos últimos %s (limite %.1f)",
+				rate, c.cfg.Window, c.cfg.DowngradeRate),
+		})
+	}
+
+	if c.cfg.AllowUpgrade && !c.stableSince.IsZero() && s.At.Sub(c.stableSince) >= c.cfg.StableFor {
+		to, ok := c.stepUp(current)
+		if !ok {
+			return nil
+		}
+		return c.decide(s.At, &AdaptiveDecision{
+			From:      current,
+			To:        to,
+			Direction: "up",
+			Rate:      rate,
+			Reason:    fmt.Sprintf("reprodução estável há %s", s.At.Sub(c.stableSince).Round(time.Second)),
+		})
+	}
+
+	return nil
+}
+
+func (c *AdaptiveController) decide(at time.Time, d *AdaptiveDecision) *AdaptiveDecision {
+	c.lastChange = at
+	c.Reset()
+	return d
+}
+
+// modeTier retorna o tier de GPU de um modo (-1 se desconhecido)
+func modeTier(mode PerformanceMode) int {
+	if pr, ok := GetPreset(mode); ok {
+		return pr.GPUTier
+	}
+	return -1
+}
+
+// stepDown retorna o nível da escada imediatamente abaixo do modo atual
+func (c *AdaptiveController) stepDown(current PerformanceMode) (PerformanceMode, bool) {
+	cur := modeTier(current)
+	best, bestTier := PerformanceMode(""), -1
+	for _, m := range c.cfg.Ladder {
+		t := modeTier(m)
+		if t >= 0 && t < cur && t > bestTier {
+			best, bestTier = m, t
+		}
+	}
+	return best, best != ""
+}
+
+// stepUp retorna o nível da escada imediatamente acima, sem passar do teto
+func (c *AdaptiveController) stepUp(current PerformanceMode) (PerformanceMode, bool) {
+	cur, limit := modeTier(current), modeTier(c.ceiling)
+	best, bestTier := PerformanceMode(""), int(^uint(0)>>1)
+	for _, m := range c.cfg.Ladder {
+		t := modeTier(m)
+		if t > cur && t <= limit && t < bestTier {
+			best, bestTier = m, t
+		}
+	}
+	return best, best != ""
+}
+
+// --- Integração com o Player ---
+
+// EnableAdaptiveQuality liga/desliga o ajuste automático de qualidade
+// O modo atual vira o teto: o controlador só desce e volta até ele
+func (p *Player) EnableAdaptiveQuality(enable bool) {
+	p.SetAdaptiveConfig(enable, DefaultAdaptiveConfig())
+}
+
+// SetAdaptiveConfig liga o ajuste automático com uma configuração específica
+func (p *Player) SetAdaptiveConfig(enable bool, cfg AdaptiveConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !enable {
+		p.adaptive = nil
+		return
+	}
+	p.adaptive = NewAdaptiveController(cfg, p.currentMode)
+	p.lastSample = time.Time{}
+}
+
+// IsAdaptiveQuality indica se o ajuste automático está ligado
+func (p *Player) IsAdaptiveQuality() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.adaptive != nil
+}
+
+// sampleFrames lê os contadores de frames do engine
+func (p *Player) sampleFrames(now time.Time) FrameSample {
+	return FrameSample{
+		At:      now,
+		Dropped: p.getInt64("frame-drop-count"),
+		Decoder: p.getInt64("decoder-frame-drop-count"),
+		Delayed: p.getInt64("vo-delayed-frame-count"),
+	}
+}
+
+// tickAdaptive coleta uma amostra (se já passou o intervalo) e aplica a decisão
+// Chamado pelo loop de eventos; exposto aos testes através do FakeEngine
+func (p *Player) tickAdaptive(now time.Time) *AdaptiveDecision {
+	p.mu.Lock()
+	ctrl := p.adaptive
+	if ctrl == nil || p.snapshot.State != StatePlaying || p.animePreset != "" {
+		p.mu.Unlock()
+		return nil
+	}
+	if !p.lastSample.IsZero() && now.Sub(p.lastSample) < ctrl.cfg.Interval {
+		p.mu.Unlock()
+		return nil
+	}
+	p.lastSample = now
+	current := p.currentMode
+	decision := ctrl.Observe(p.sampleFrames(now), current)
+	p.mu.Unlock()
+
+	if decision == nil {
+		return nil
+	}
+
+	icon := "⬇️"
+	if decision.Direction == "up" {
+		icon = "⬆️"
+	}
+	fmt.Printf("%s Qualidade automática: %s → %s (%s)\n", icon, decision.From, decision.To, decision.Reason)
+
+	p.setPerformanceMode(decision.To, ModeChange{
+		Mode:     decision.To,
+		Reason:   decision.Reason,
+		Adaptive: decision,
+	})
+	return decision
+}
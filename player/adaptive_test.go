@@ -0,0 +1,189 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+// frameFeed gera amostras a cada segundo com uma taxa fixa de frames perdidos
+type frameFeed struct {
+	at      time.Time
+	dropped int64
+}
+
+func newFrameFeed() *frameFeed {
+	return &frameFeed{at: time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)}
+}
+
+// next avança um segundo com perSecond frames perdidos nesse segundo
+func (f *frameFeed) next(perSecond int64) FrameSample {
+	f.at = f.at.Add(time.Second)
+	f.dropped += perSecond
+	return FrameSample{At: f.at, Dropped: f.dropped}
+}
+
+func TestAdaptiveDowngradeAndCooldown(t *testing.T) {
+	cfg := DefaultAdaptiveConfig()
+	ctrl := NewAdaptiveController(cfg, ModeHigh)
+	feed := newFrameFeed()
+	current := ModeHigh
+
+	// Antes de meia janela não há dados suficientes para decidir
+	for i := 0; i < 5; i++ {
+		if d := ctrl.Observe(feed.next(5), current); d != nil {
+			t.Fatalf("decisão com %d amostras: %+v", i+1, d)
+		}
+	}
+
+	d := ctrl.Observe(feed.next(5), current)
+	if d == nil || d.Direction != "down" || d.From != ModeHigh || d.To != ModeMedium {
+		t.Fatalf("decisão = %+v, want high → medium", d)
+	}
+	if d.Rate < cfg.DowngradeRate {
+		t.Errorf("Rate = %.1f, want >= %.1f", d.Rate, cfg.DowngradeRate)
+	}
+	current = d.To
+	changedAt := feed.at
+
+	// Cooldown: continua engasgando, mas nada muda antes de 15s
+	var next *AdaptiveDecision
+	for next == nil {
+		next = ctrl.Observe(feed.next(5), current)
+		if feed.at.Sub(changedAt) > time.Minute {
+			t.Fatal("não desceu de novo depois do cooldown")
+		}
+	}
+	if elapsed := feed.at.Sub(changedAt); elapsed < cfg.Cooldown {
+		t.Errorf("desceu de novo depois de %s, want >= %s", elapsed, cfg.Cooldown)
+	}
+	if next.From != ModeMedium || next.To != ModeLow {
+		t.Errorf("decisão = %+v, want medium → low", next)
+	}
+	current = next.To
+
+	// No nível mais baixo não há para onde descer
+	for i := 0; i < 60; i++ {
+		if d := ctrl.Observe(feed.next(5), current); d != nil {
+			t.Fatalf("decisão abaixo do modo leve: %+v", d)
+		}
+	}
+}
+
+func TestAdaptiveUpgradeCeiling(t *testing.T) {
+	cfg := DefaultAdaptiveConfig()
+	ctrl := NewAdaptiveController(cfg, ModeMedium)
+	feed := newFrameFeed()
+	current := ModeLow
+
+	var up *AdaptiveDecision
+	for up == nil {
+		up = ctrl.Observe(feed.next(0), current)
+		if feed.at.Sub(newFrameFeed().at) > 5*time.Minute {
+			t.Fatal("não subiu com a reprodução estável")
+		}
+	}
+	if up.Direction != "up" || up.From != ModeLow || up.To != ModeMedium {
+		t.Fatalf("decisão = %+v, want low → medium", up)
+	}
+	if elapsed := feed.at.Sub(newFrameFeed().at); elapsed < cfg.StableFor {
+		t.Errorf("subiu depois de %s, want >= %s", elapsed, cfg.StableFor)
+	}
+	current = up.To
+
+	// O teto é o modo escolhido pelo usuário: nunca passa dele
+	for i := 0; i < 600; i++ {
+		if d := ctrl.Observe(feed.next(0), current); d != nil {
+			t.Fatalf("subiu acima do teto: %+v", d)
+		}
+	}
+}
+
+func TestAdaptiveInstability(t *testing.T) {
+	cfg := DefaultAdaptiveConfig()
+	cfg.StableFor = 30 * time.Second
+	ctrl := NewAdaptiveController(cfg, ModeHigh)
+	feed := newFrameFeed()
+
+	// Um soluço abaixo do limite de descida zera o tempo estável
+	for i := 0; i < 25; i++ {
+		ctrl.Observe(feed.next(0), ModeMedium)
+	}
+	ctrl.Observe(feed.next(1), ModeMedium)
+	for i := 0; i < 20; i++ {
+		if d := ctrl.Observe(feed.next(0), ModeMedium); d != nil {
+			t.Fatalf("subiu logo depois de um soluço: %+v", d)
+		}
+	}
+}
+
+func TestAdaptiveCounterReset(t *testing.T) {
+	ctrl := NewAdaptiveController(DefaultAdaptiveConfig(), ModeHigh)
+	feed := newFrameFeed()
+
+	for i := 0; i < 4; i++ {
+		ctrl.Observe(feed.next(5), ModeHigh)
+	}
+
+	// Novo arquivo: os contadores do MPV voltam a zero e as amostras antigas são descartadas
+	feed.dropped = 0
+	if d := ctrl.Observe(feed.next(0), ModeHigh); d != nil {
+		t.Fatalf("decisão depois de zerar os contadores: %+v", d)
+	}
+	if rate := ctrl.Rate(); rate != 0 {
+		t.Errorf("Rate depois de zerar = %.1f, want 0", rate)
+	}
+}
+
+func TestTickAdaptive(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	if err := p.SetPerformanceMode(ModeHigh); err != nil {
+		t.Fatal(err)
+	}
+	p.EnableAdaptiveQuality(true)
+
+	// Parado não amostra
+	now := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+	if d := p.tickAdaptive(now); d != nil {
+		t.Fatalf("decisão sem reproduzir: %+v", d)
+	}
+
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) { s.Idle = false })
+	if got := p.State(); got != StatePlaying {
+		t.Fatalf("State = %s, want %s", got, StatePlaying)
+	}
+
+	var decision *AdaptiveDecision
+	var dropped, delayed int64
+	for i := 0; i < 30 && decision == nil; i++ {
+		now = now.Add(time.Second)
+		dropped += 3
+		delayed++
+		e.SetValue("frame-drop-count", dropped)
+		e.SetValue("decoder-frame-drop-count", int64(0))
+		e.SetValue("vo-delayed-frame-count", delayed)
+
+		decision = p.tickAdaptive(now)
+
+		// Uma segunda chamada no mesmo intervalo não gera amostra
+		if d := p.tickAdaptive(now.Add(100 * time.Millisecond)); d != nil {
+			t.Fatalf("amostra antes do intervalo: %+v", d)
+		}
+	}
+
+	if decision == nil || decision.To != ModeMedium {
+		t.Fatalf("decisão = %+v, want high → medium", decision)
+	}
+	if got := p.GetCurrentMode(); got != ModeMedium {
+		t.Errorf("GetCurrentMode = %s, want %s", got, ModeMedium)
+	}
+	medium, _ := GetPreset(ModeMedium)
+	for _, prop := range medium.Properties {
+		if got, _ := e.Property(prop.Name); got != prop.Value {
+			t.Errorf("%s = %q, want %q", prop.Name, got, prop.Value)
+		}
+	}
+	if !p.IsAdaptiveQuality() {
+		t.Error("qualidade automática desligou depois de descer")
+	}
+}
@@ -0,0 +1,151 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnimePreset identifica uma pipeline Anime4K (ex: "A-HQ", "C+A-Fast")
+type AnimePreset string
+
+// DefaultAnimePreset é a pipeline usada por SetAnimeMode(true)
+const DefaultAnimePreset AnimePreset = "A-HQ"
+
+// AnimePipeline descreve uma combinação de shaders Anime4K
+// As cadeias seguem as instruções oficiais do Anime4K v4 para mpv
+type AnimePipeline struct {
+	ID          AnimePreset `json:"id"`
+	Mode        string      `json:"mode"` // "A", "B", "C", "A+A", "B+B", "C+A"
+	Tier        string      `json:"tier"` // "Fast" ou "HQ"
+	Description string      `json:"description"`
+	Shaders     []string    `json:"shaders"`
+}
+
+// animeModes descreve para que tipo de fonte cada modo foi feito
+var animeModes = []struct {
+	mode        string
+	description string
+}{
+	{"A", "Fontes 1080p com blur de upscale (maioria dos animes modernos)"},
+	{"B", "Fontes 720p com aliasing/ringing de downscale (restauração suave)"},
+	{"C", "Fontes 480p/DVD com ruído (upscale com denoise)"},
+	{"A+A", "Modo A com restauração extra (mais nitidez, mais custo)"},
+	{"B+B", "Modo B com restauração suave extra"},
+	{"C+A", "Modo C seguido de restauração do modo A"},
+}
+
+// animeChain monta a cadeia de um modo para um tier
+// big = modelo principal (VL no HQ, M no Fast), small = modelo final (M no HQ, S no Fast)
+func animeChain(mode, big, small string) []string {
+	restore := "Anime4K_Restore_CNN_" + big
+	restoreSoft := "Anime4K_Restore_CNN_Soft_" + big
+	upscale := "Anime4K_Upscale_CNN_x2_" + big
+	denoise := "Anime4K_Upscale_Denoise_CNN_x2_" + big
+	downscale := []string{"Anime4K_AutoDownscalePre_x2", "Anime4K_AutoDownscalePre_x4"}
+	final := "Anime4K_Upscale_CNN_x2_" + small
+
+	chain := []string{"Anime4K_Clamp_Highlights"}
+	switch mode {
+	case "A":
+		chain = append(chain, restore, upscale)
+		chain = append(chain, downscale...)
+	case "B":
+		chain = append(chain, restoreSoft, upscale)
+		chain = append(chain, downscale...)
+	case "C":
+		chain = append(chain, denoise)
+		chain = append(chain, downscale...)
+	case "A+A":
+		chain = append(chain, restore, upscale, "Anime4K_Restore_CNN_"+small)
+		chain = append(chain, downscale...)
+	case "B+B":
+		chain = append(chain, restoreSoft, upscale)
+		chain = append(chain, downscale...)
+		chain = append(chain, "Anime4K_Restore_CNN_Soft_"+small)
+	case "C+A":
+		chain = append(chain, denoise)
+		chain = append(chain, downscale...)
+		chain = append(chain, "Anime4K_Restore_CNN_"+small)
+	}
+	return append(chain, final)
+}
+
+// GetAnimePipelines retorna todas as pipelines Anime4K (HQ e Fast)
+func GetAnimePipelines() []AnimePipeline {
+	var list []AnimePipeline
+	for _, tier := range []struct{ name, big, small string }{
+		{"HQ", "VL", "M"},
+		{"Fast", "M", "S"},
+	} {
+		for _, m := range animeModes {
+			list = append(list, AnimePipeline{
+				ID:          AnimePreset(m.mode + "-" + tier.name),
+				Mode:        m.mode,
+				Tier:        tier.name,
+				Description: m.description,
+				Shaders:     animeChain(m.mode, tier.big, tier.small),
+			})
+		}
+	}
+	return list
+}
+
+// GetAnimePipeline retorna uma pipeline pelo ID (sem diferenciar maiúsculas)
+func GetAnimePipeline(id AnimePreset) (AnimePipeline, bool) {
+	for _, pl := range GetAnimePipelines() {
+		if strings.EqualFold(string(pl.ID), string(id)) {
+			return pl, true
+		}
+	}
+	return AnimePipeline{}, false
+}
+
+// ParseAnimePreset converte texto (ex: "c+a-fast") em uma pipeline conhecida
+func ParseAnimePreset(s string) (AnimePreset, bool) {
+	pl, ok := GetAnimePipeline(AnimePreset(strings.TrimSpace(s)))
+	return pl.ID, ok
+}
+
+// Available indica se todos os shaders da pipeline estão presentes e íntegros
+func (pl AnimePipeline) Available(report ShaderReport) bool {
+	status := make(map[string]ShaderStatus, len(report.Checks))
+	for _, c := range report.Checks {
+		status[c.Name] = c.Status
+	}
+	for _, s := range pl.Shaders {
+		if status[s] != ShaderOK {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAnimePreset aplica uma pipeline Anime4K
+// Os shaders são validados pelo manifesto antes de serem enviados ao MPV
+func (p *Player) SetAnimePreset(id AnimePreset) error {
+	pl, ok := GetAnimePipeline(id)
+	if !ok {
+		return fmt.Errorf("preset Anime4K desconhecido: %s", id)
+	}
+
+	p.mu.Lock()
+	// Limpar shaders anteriores
+	p.engine.SetPropertyString("glsl-shaders", "")
+	p.appendShaders(pl.Shaders)
+	p.animePreset = pl.ID
+	shaders := PropertyList{{"glsl-shaders", p.getString("glsl-shaders")}}
+	p.recordBundleConflicts(overrideAnime, p.bundle.Conflicts(shaders, "Anime4K "+string(pl.ID)))
+	p.mu.Unlock()
+
+	fmt.Printf("🎌 Modo Anime ativado (Anime4K %s %s)\n", pl.Mode, pl.Tier)
+	p.recordProfile(func(sp *SeriesProfile) { sp.AnimePreset = pl.ID })
+	return nil
+}
+
+// GetAnimePreset retorna a pipeline Anime4K ativa ("" se desativada)
+func (p *Player) GetAnimePreset() AnimePreset {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.animePreset
+}
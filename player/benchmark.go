@@ -0,0 +1,268 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BenchmarkOptions controla o benchmark de presets
+type BenchmarkOptions struct {
+	Width      int           // resolução do clipe de teste
+	Height     int           // (menor que a tela para forçar upscale)
+	FPS        int           // frames por segundo do clipe
+	Warmup     time.Duration // tempo ignorado no início (compilação de shaders)
+	Duration   time.Duration // tempo medido por preset
+	Headroom   float64       // fração do tempo de frame que o preset pode usar
+	NullOutput bool          // força vo=null (máquinas sem GPU / CI)
+	Persist    bool          // grava o modo recomendado no player4k.json
+}
+
+// DefaultBenchmarkOptions retorna opções padrão (720p 24fps, 5s por preset)
+func DefaultBenchmarkOptions() BenchmarkOptions {
+	return BenchmarkOptions{
+		Width:    1280,
+		Height:   720,
+		FPS:      24,
+		Warmup:   2 * time.Second,
+		Duration: 5 * time.Second,
+		Headroom: 0.5,
+		Persist:  true,
+	}
+}
+
+// source retorna o clipe gerado pelo libavfilter (não precisa de arquivo)
+func (o BenchmarkOptions) source() string {
+	seconds := int((o.Warmup + o.Duration).Seconds()) + 5
+	return fmt.Sprintf("av://lavfi:testsrc2=size=%dx%d:rate=%d:duration=%d", o.Width, o.Height, o.FPS, seconds)
+}
+
+// BenchmarkRun é o resultado de um preset
+type BenchmarkRun struct {
+	Mode            PerformanceMode `json:"mode"`
+	FrameTimeMs     float64         `json:"frameTimeMs"`     // média do tempo de render por frame
+	PeakFrameTimeMs float64         `json:"peakFrameTimeMs"` // maior média observada
+	DroppedFrames   int64           `json:"droppedFrames"`
+	DelayedFrames   int64           `json:"delayedFrames"`
+	VO              string          `json:"vo"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// BenchmarkResult é o resultado completo do benchmark
+type BenchmarkResult struct {
+	Runs          []BenchmarkRun  `json:"runs"`
+	Recommended   PerformanceMode `json:"recommended"`
+	Reason        string          `json:"reason"`
+	FrameBudgetMs float64         `json:"frameBudgetMs"`
+	NullOutput    bool            `json:"nullOutput"`
+	At            time.Time       `json:"at"`
+}
+
+// errVoInit indica que a saída de vídeo não inicializou (sem GPU)
+var errVoInit = errors.New("saída de vídeo não inicializou")
+
+// Benchmark reproduz um clipe de teste com cada preset e recomenda um modo
+// Usa o próprio loop de eventos, então não deve rodar junto com Run
+func (p *Player) Benchmark(opts BenchmarkOptions) (*BenchmarkResult, error) {
+	if opts.FPS <= 0 || opts.Duration <= 0 {
+		return nil, fmt.Errorf("benchmark: FPS e duração precisam ser positivos")
+	}
+
+	previous := p.GetCurrentMode()
+	defer p.SetPerformanceMode(previous)
+
+	runs, err := p.benchmarkAll(opts)
+
+	// Sem GPU: refaz tudo com vo=null em vez de falhar
+	if errors.Is(err, errVoInit) && !opts.NullOutput {
+		fmt.Println("⚠️ Saída de vídeo por GPU falhou, repetindo com vo=null")
+		opts.NullOutput = true
+		runs, err = p.benchmarkAll(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BenchmarkResult{
+		Runs:          runs,
+		FrameBudgetMs: 1000 / float64(opts.FPS),
+		NullOutput:    opts.NullOutput,
+		At:            time.Now(),
+	}
+	result.Recommended, result.Reason = recommendMode(runs, result.FrameBudgetMs, opts)
+	fmt.Printf("🏁 Modo recomendado: %s (%s)\n", result.Recommended, result.Reason)
+
+	if opts.Persist {
+		cfg, err := LoadUserConfig()
+		if err != nil {
+			return result, err
+		}
+		cfg.RecommendedMode = result.Recommended
+		cfg.Benchmark = result
+		if err := cfg.Save(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// benchmarkAll mede todos os presets, do mais leve ao mais pesado
+func (p *Player) benchmarkAll(opts BenchmarkOptions) ([]BenchmarkRun, error) {
+	var runs []BenchmarkRun
+	for _, preset := range GetAllPresets() {
+		fmt.Printf("⏱️ Benchmark: %s %s...\n", preset.Icon, preset.Name)
+		run, err := p.benchmarkPreset(preset.ID, opts)
+		if err != nil {
+			return runs, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// recommendMode escolhe o preset mais pesado que cabe no orçamento de frame
+func recommendMode(runs []BenchmarkRun, budgetMs float64, opts BenchmarkOptions) (PerformanceMode, string) {
+	if opts.NullOutput {
+		return ModeLow, "sem GPU disponível: benchmark rodou com vo=null, nada pôde ser medido"
+	}
+
+	limit := budgetMs * opts.Headroom
+	measured := false
+
+	// Runs estão em ordem crescente de tier; procura do mais pesado para o mais leve
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		if r.Error != "" || r.FrameTimeMs <= 0 {
+			continue
+		}
+		measured = true
+		if r.FrameTimeMs <= limit && r.DroppedFrames == 0 {
+			return r.Mode, fmt.Sprintf("%.2f ms por frame, dentro do limite de %.2f ms (%.0f%% de %.2f ms)",
+				r.FrameTimeMs, limit, opts.Headroom*100, budgetMs)
+		}
+	}
+
+	if !measured {
+		return ModeLow, "vo-passes indisponível: a saída de vídeo não expõe tempos de GPU (renderização por software?)"
+	}
+	return ModeLow, fmt.Sprintf("nenhum preset ficou abaixo de %.2f ms por frame sem perder frames", limit)
+}
+
+// benchmarkPreset mede um preset reproduzindo o clipe de teste
+func (p *Player) benchmarkPreset(mode PerformanceMode, opts BenchmarkOptions) (BenchmarkRun, error) {
+	run := BenchmarkRun{Mode: mode}
+
+	if err := p.SetPerformanceMode(mode); err != nil {
+		run.Error = err.Error()
+		return run, nil
+	}
+	if opts.NullOutput {
+		p.engine.SetPropertyString("vo", "null")
+	}
+
+	if err := p.engine.Command([]string{"loadfile", opts.source()}); err != nil {
+		run.Error = err.Error()
+		return run, nil
+	}
+	defer p.engine.Command([]string{"stop"})
+
+	var loadedAt, lastPoll time.Time
+	var total float64
+	var count int
+	deadline := time.Now().Add(opts.Warmup + opts.Duration + 15*time.Second)
+
+	for time.Now().Before(deadline) {
+		ev := p.engine.WaitEvent(0.1)
+		now := time.Now()
+
+		if ev != nil {
+			switch ev.ID {
+			case EngineFileLoaded:
+				loadedAt = now
+				p.engine.SetPropertyString("pause", "no")
+			case EngineEnd:
+				if ev.EndFile != nil && ev.EndFile.Reason == EndFileError {
+					if errors.Is(ev.EndFile.Error, ErrVoInitFailed) {
+						run.Error = ev.EndFile.Error.Error()
+						return run, errVoInit
+					}
+					run.Error = fmt.Sprint(ev.EndFile.Error)
+					return run, nil
+				}
+				if !loadedAt.IsZero() {
+					deadline = now
+				}
+			}
+		}
+
+		if loadedAt.IsZero() || now.Sub(loadedAt) < opts.Warmup {
+			continue
+		}
+		if now.Sub(loadedAt) >= opts.Warmup+opts.Duration {
+			break
+		}
+
+		// Amostrar os tempos de render a cada 500ms
+		if now.Sub(lastPoll) >= 500*time.Millisecond {
+			lastPoll = now
+			if ms, ok := p.readFrameTime(); ok {
+				total += ms
+				count++
+				if ms > run.PeakFrameTimeMs {
+					run.PeakFrameTimeMs = ms
+				}
+			}
+		}
+	}
+
+	if loadedAt.IsZero() {
+		run.Error = "clipe de teste não carregou (MPV sem lavfi?)"
+		return run, nil
+	}
+
+	if count > 0 {
+		run.FrameTimeMs = total / float64(count)
+	}
+	run.DroppedFrames = p.getInt64("frame-drop-count")
+	run.DelayedFrames = p.getInt64("vo-delayed-frame-count")
+	if vo, err := p.engine.GetProperty("current-vo", FormatString); err == nil {
+		run.VO, _ = vo.(string)
+	}
+
+	return run, nil
+}
+
+// voPass é um passo de render em vo-passes (tempos em nanossegundos)
+type voPass struct {
+	Desc string `json:"desc"`
+	Last int64  `json:"last"`
+	Avg  int64  `json:"avg"`
+	Peak int64  `json:"peak"`
+}
+
+// readFrameTime soma o tempo médio de todos os passos "fresh" de vo-passes
+func (p *Player) readFrameTime() (float64, bool) {
+	val, err := p.engine.GetProperty("vo-passes", FormatString)
+	if err != nil {
+		return 0, false
+	}
+	s, _ := val.(string)
+	return parseVoPasses(s)
+}
+
+// parseVoPasses converte o JSON de vo-passes em milissegundos por frame
+func parseVoPasses(s string) (float64, bool) {
+	var passes struct {
+		Fresh []voPass `json:"fresh"`
+	}
+	if err := json.Unmarshal([]byte(s), &passes); err != nil || len(passes.Fresh) == 0 {
+		return 0, false
+	}
+
+	var ns int64
+	for _, pass := range passes.Fresh {
+		ns += pass.Avg
+	}
+	return float64(ns) / 1e6, ns > 0
+}
@@ -0,0 +1,445 @@
+package player
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// ConfigBundleKind é a origem da pasta de configuração do MPV
+type ConfigBundleKind string
+
+const (
+	BundlePortable ConfigBundleKind = "portable" // mpv/portable_config ao lado do executável
+	BundleUser     ConfigBundleKind = "user"     // pasta do MPV do usuário (~/.config/mpv, %APPDATA%\mpv)
+	BundleNone     ConfigBundleKind = "none"     // só a configuração aplicada pelo Go
+)
+
+// ParseConfigBundleKind converte texto em ConfigBundleKind
+func ParseConfigBundleKind(s string) (ConfigBundleKind, bool) {
+	switch k := ConfigBundleKind(strings.ToLower(strings.TrimSpace(s))); k {
+	case BundlePortable, BundleUser, BundleNone:
+		return k, true
+	}
+	return "", false
+}
+
+// ConfigBundle é uma pasta de configuração do MPV (mpv.conf, input.conf, scripts, fontes)
+// O MPV carrega a pasta sozinho na inicialização (config=yes + config-dir); o Go só
+// lê o mpv.conf para não desfazer as escolhas dele e para apontar conflitos
+//
+// Ordem de aplicação:
+//
+//	padrões do Go < mpv.conf do pacote < player4k.json/flags < modo de qualidade
+type ConfigBundle struct {
+	Kind     ConfigBundleKind `json:"kind"`
+	Dir      string           `json:"dir"`
+	Options  PropertyList     `json:"options"`           // opções fora de [perfis] do mpv.conf, na ordem do arquivo
+	Scripts  []string         `json:"scripts"`           // scripts em scripts/ (arquivos .lua/.js e pastas com main.lua)
+	HasInput bool             `json:"hasInput"`          // input.conf presente
+	SkipKey  string           `json:"skipKey,omitempty"` // tecla de skip-segment no input.conf
+}
+
+// BundleConflict é uma opção do mpv.conf trocada pelo player
+type BundleConflict struct {
+	Property string `json:"property"`
+	Bundle   string `json:"bundle"` // valor no mpv.conf
+	Value    string `json:"value"`  // valor aplicado pelo player
+	Source   string `json:"source"` // quem trocou (ex: "modo high", "sistema")
+}
+
+// String descreve o conflito em uma linha
+func (c BundleConflict) String() string {
+	return fmt.Sprintf("%s: %s → %s (%s)", c.Property, c.Bundle, c.Value, c.Source)
+}
+
+// PortableBundleDirs retorna as pastas procuradas para o pacote portátil, em ordem:
+// mpv/portable_config e portable_config ao lado do executável e na pasta atual
+func PortableBundleDirs() []string {
+	var bases []string
+	if exe, err := os.Executable(); err == nil {
+		bases = append(bases, filepath.Dir(exe))
+	}
+	if wd, err := filepath.Abs("."); err == nil {
+		bases = append(bases, wd)
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, base := range bases {
+		for _, rel := range []string{filepath.Join("mpv", "portable_config"), "portable_config"} {
+			dir := filepath.Join(base, rel)
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// UserBundleDir retorna a pasta de configuração do MPV do usuário
+// Windows: %APPDATA%\mpv; demais sistemas: $XDG_CONFIG_HOME/mpv ou ~/.config/mpv
+func UserBundleDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("pasta do MPV indisponível: %w", err)
+		}
+		return filepath.Join(base, "mpv"), nil
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "mpv"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("pasta do MPV indisponível: %w", err)
+	}
+	return filepath.Join(home, ".config", "mpv"), nil
+}
+
+// OpenConfigBundle localiza e lê o pacote escolhido
+// BundleNone sempre funciona e devolve um pacote inativo
+func OpenConfigBundle(kind ConfigBundleKind) (*ConfigBundle, error) {
+	var dir string
+	switch kind {
+	case BundleNone:
+		return &ConfigBundle{Kind: BundleNone, Options: PropertyList{}, Scripts: []string{}}, nil
+	case BundlePortable:
+		dirs := PortableBundleDirs()
+		for _, d := range dirs {
+			if fileExists(filepath.Join(d, "mpv.conf")) {
+				dir = d
+				break
+			}
+		}
+		if dir == "" {
+			return nil, fmt.Errorf("portable_config não encontrada (procurado em %s)", strings.Join(dirs, ", "))
+		}
+	case BundleUser:
+		d, err := UserBundleDir()
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(d); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("pasta do MPV do usuário não encontrada: %s", d)
+		}
+		dir = d
+	default:
+		return nil, fmt.Errorf("pacote de configuração desconhecido: %q (use portable, user ou none)", kind)
+	}
+
+	return LoadConfigBundle(kind, dir)
+}
+
+// LoadConfigBundle lê uma pasta de configuração do MPV
+func LoadConfigBundle(kind ConfigBundleKind, dir string) (*ConfigBundle, error) {
+	b := &ConfigBundle{Kind: kind, Dir: dir, Options: PropertyList{}, Scripts: []string{}}
+
+	if f, err := os.Open(filepath.Join(dir, "mpv.conf")); err == nil {
+		opts, err := parseMpvConf(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(dir, "mpv.conf"), err)
+		}
+		b.Options = opts
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	b.HasInput = fileExists(filepath.Join(dir, "input.conf"))
+	if b.HasInput {
+		b.SkipKey = readSkipKey(filepath.Join(dir, "input.conf"))
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "scripts"))
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case e.IsDir() && fileExists(filepath.Join(dir, "scripts", name, "main.lua")):
+			b.Scripts = append(b.Scripts, name)
+		case !e.IsDir() && (strings.HasSuffix(name, ".lua") || strings.HasSuffix(name, ".js")):
+			b.Scripts = append(b.Scripts, strings.TrimSuffix(strings.TrimSuffix(name, ".lua"), ".js"))
+		}
+	}
+	sort.Strings(b.Scripts)
+
+	return b, nil
+}
+
+// fileExists indica se o caminho existe e é um arquivo
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// parseMpvConf lê as opções de nível superior de um mpv.conf
+// Aceita "opcao=valor", "--opcao=valor", valores entre aspas, flags sem valor ("fs")
+// e "no-opcao"; as seções [perfil] são ignoradas (só valem com profile=)
+func parseMpvConf(r io.Reader) (PropertyList, error) {
+	list := PropertyList{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			break // daqui em diante só há perfis
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(line, "--"), "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("linha %d: opção sem nome", n)
+		}
+
+		if !hasValue {
+			value = "yes"
+			if strings.HasPrefix(name, "no-") {
+				name, value = strings.TrimPrefix(name, "no-"), "no"
+			}
+		} else {
+			value = unquoteMpvValue(strings.TrimSpace(value))
+		}
+		list = append(list, Property{Name: name, Value: value})
+	}
+	return list, sc.Err()
+}
+
+// readSkipKey retorna a tecla ligada a skip-segment em um input.conf ("" se nenhuma)
+func readSkipKey(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseSkipKey(f)
+}
+
+// parseSkipKey procura a tecla de skip-segment (script-binding ou script-message)
+// Como no MPV, a última linha de cada tecla vence: uma tecla religada depois não conta
+func parseSkipKey(r io.Reader) string {
+	var keys []string
+	bindings := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		key := fields[0]
+		if _, seen := bindings[key]; !seen {
+			keys = append(keys, key)
+		}
+		bindings[key] = strings.Join(fields[1:], " ")
+	}
+
+	for _, key := range keys {
+		if strings.HasSuffix(bindings[key], "skip-segment") {
+			return key
+		}
+	}
+	return ""
+}
+
+// unquoteMpvValue tira as aspas de um valor ou o comentário no fim da linha
+func unquoteMpvValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') {
+		if end := strings.IndexByte(v[1:], v[0]); end >= 0 {
+			return v[1 : end+1]
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v
+}
+
+// Active indica se o pacote é carregado pelo MPV
+func (b *ConfigBundle) Active() bool {
+	return b != nil && b.Kind != BundleNone && b.Dir != ""
+}
+
+// Option retorna o último valor de uma opção no mpv.conf
+func (b *ConfigBundle) Option(name string) (string, bool) {
+	if !b.Active() {
+		return "", false
+	}
+	for i := len(b.Options) - 1; i >= 0; i-- {
+		if b.Options[i].Name == name {
+			return b.Options[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// engineOptions são as opções passadas ao MPV antes da inicialização
+func (b *ConfigBundle) engineOptions() []Property {
+	if !b.Active() {
+		return nil
+	}
+	return []Property{{"config", "yes"}, {"config-dir", b.Dir}}
+}
+
+// Conflicts compara propriedades aplicadas pelo player com o mpv.conf
+func (b *ConfigBundle) Conflicts(props PropertyList, source string) []BundleConflict {
+	conflicts := []BundleConflict{}
+	for _, prop := range props {
+		if value, ok := b.Option(prop.Name); ok && !sameMpvValue(value, prop.Value) {
+			conflicts = append(conflicts, BundleConflict{Property: prop.Name, Bundle: value, Value: prop.Value, Source: source})
+		}
+	}
+	return conflicts
+}
+
+// sameMpvValue compara valores do MPV ignorando maiúsculas e números equivalentes ("1.0" e "1")
+func sameMpvValue(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	var fa, fb float64
+	if _, err := fmt.Sscan(a, &fa); err == nil {
+		if _, err := fmt.Sscan(b, &fb); err == nil {
+			return fa == fb
+		}
+	}
+	return false
+}
+
+// PlatformConflicts são as opções do mpv.conf que não funcionam neste sistema
+// (ex: gpu-api=d3d11 fora do Windows); o player troca por "auto"
+func (b *ConfigBundle) PlatformConflicts() []BundleConflict {
+	conflicts := []BundleConflict{}
+	if api, ok := b.Option("gpu-api"); ok && !containsFold(platform.GPUAPIs, api) {
+		conflicts = append(conflicts, BundleConflict{Property: "gpu-api", Bundle: api, Value: "auto", Source: "sistema"})
+	}
+	return conflicts
+}
+
+// containsFold indica se a lista contém s (sem diferenciar maiúsculas)
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Integração com o Player ---
+
+// NewWithBundle cria o player com uma pasta de configuração do MPV
+// nil ou BundleNone equivalem a New()
+func NewWithBundle(bundle *ConfigBundle) (*Player, error) {
+	engine, err := newEngine(bundle.engineOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Active() {
+		fmt.Printf("📄 Configuração do MPV: %s (%d opções, scripts: %s)\n",
+			bundle.Dir, len(bundle.Options), strings.Join(bundle.Scripts, ", "))
+	}
+
+	return newPlayer(engine, bundle), nil
+}
+
+// setBase aplica um padrão do Go, a menos que o mpv.conf do pacote defina a opção
+func (p *Player) setBase(name, value string) {
+	if _, ok := p.bundle.Option(name); ok {
+		return
+	}
+	p.engine.SetPropertyString(name, value)
+}
+
+// reconcileBundle troca as opções do pacote que não funcionam neste sistema
+func (p *Player) reconcileBundle() {
+	for _, c := range p.bundle.PlatformConflicts() {
+		fmt.Printf("⚠️ mpv.conf: %s\n", c)
+		p.engine.SetPropertyString(c.Property, c.Value)
+	}
+}
+
+// Origens das propriedades aplicadas pelo Go por cima do mpv.conf, na ordem de aplicação
+const (
+	overrideSettings      = "configuração" // player4k.json, ambiente e flags
+	overrideMode          = "modo"         // preset do modo de qualidade
+	overrideAnime         = "anime4k"      // pipeline Anime4K (glsl-shaders)
+	overrideInterpolation = "interpolação" // EnableInterpolation
+)
+
+var overrideOrder = []string{overrideSettings, overrideMode, overrideAnime, overrideInterpolation}
+
+// recordBundleConflicts guarda e mostra o que uma origem trocou do mpv.conf
+// Substitui o que a mesma origem tinha trocado antes (ex: o modo anterior)
+// Deve ser chamado com p.mu travado
+func (p *Player) recordBundleConflicts(origin string, conflicts []BundleConflict) {
+	if !p.bundle.Active() {
+		return
+	}
+	if p.bundleOverrides == nil {
+		p.bundleOverrides = make(map[string][]BundleConflict)
+	}
+	p.bundleOverrides[origin] = conflicts
+
+	var sources []string
+	names := map[string][]string{}
+	for _, c := range conflicts {
+		if names[c.Source] == nil {
+			sources = append(sources, c.Source)
+		}
+		names[c.Source] = append(names[c.Source], c.Property)
+	}
+	for _, source := range sources {
+		fmt.Printf("  ⚠️ mpv.conf: %s substitui %s\n", source, strings.Join(names[source], ", "))
+	}
+}
+
+// presetProperties são as propriedades que um modo aplica, com os shaders
+// (setPerformanceMode sempre troca glsl-shaders, mesmo para uma lista vazia)
+func (p *Player) presetProperties(preset *Preset) PropertyList {
+	props := append(PropertyList{}, preset.Properties...)
+	return append(props, Property{"glsl-shaders", p.getString("glsl-shaders")})
+}
+
+// ConfigBundle retorna a pasta de configuração do MPV em uso (nil se nenhuma)
+func (p *Player) ConfigBundle() *ConfigBundle {
+	if !p.bundle.Active() {
+		return nil
+	}
+	return p.bundle
+}
+
+// BundleConflicts retorna as opções do mpv.conf trocadas pelo sistema e pelo player
+// (configuração, modo atual, Anime4K e interpolação)
+func (p *Player) BundleConflicts() []BundleConflict {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conflicts := p.bundle.PlatformConflicts()
+	for _, origin := range overrideOrder {
+		conflicts = append(conflicts, p.bundleOverrides[origin]...)
+	}
+	return conflicts
+}
+
+// OpenConfigBundleOrNone abre o pacote escolhido; se ele não existir, devolve
+// BundleNone junto com o erro para o chamador avisar e seguir sem pacote
+func OpenConfigBundleOrNone(kind ConfigBundleKind) (*ConfigBundle, error) {
+	bundle, err := OpenConfigBundle(kind)
+	if err != nil {
+		none, _ := OpenConfigBundle(BundleNone)
+		return none, err
+	}
+	return bundle, nil
+}
@@ -0,0 +1,133 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Engine abstrai o backend de reprodução (libmpv)
+// Permite trocar a implementação real por um fake em testes
+type Engine interface {
+	SetOptionString(name, value string) error
+	SetPropertyString(name, value string) error
+	SetProperty(name string, format Format, data interface{}) error
+	GetProperty(name string, format Format) (interface{}, error)
+	Command(cmd []string) error
+	ObserveProperty(id uint64, name string, format Format) error
+	WaitEvent(timeout float64) *EngineEvent
+	Wakeup() // interrompe um WaitEvent em andamento
+	TerminateDestroy()
+}
+
+// Format é o formato de uma propriedade (mesmos valores do mpv_format)
+type Format uint32
+
+const (
+	FormatNone Format = iota
+	FormatString
+	FormatOsdString
+	FormatFlag
+	FormatInt64
+	FormatDouble
+)
+
+// EngineEventID identifica um evento do backend (mesmos valores do mpv_event_id)
+type EngineEventID uint32
+
+const (
+	EngineNone           EngineEventID = 0
+	EngineShutdown       EngineEventID = 1
+	EngineStart          EngineEventID = 6
+	EngineEnd            EngineEventID = 7
+	EngineFileLoaded     EngineEventID = 8
+	EngineClientMessage  EngineEventID = 16
+	EnginePropertyChange EngineEventID = 22
+)
+
+// EndReason é o motivo do fim de um arquivo (mesmos valores do mpv_end_file_reason)
+type EndReason uint32
+
+const (
+	EndFileEOF      EndReason = 0
+	EndFileStop     EndReason = 2
+	EndFileQuit     EndReason = 3
+	EndFileError    EndReason = 4
+	EndFileRedirect EndReason = 5
+)
+
+func (r EndReason) String() string {
+	switch r {
+	case EndFileEOF:
+		return "eof"
+	case EndFileStop:
+		return "stop"
+	case EndFileQuit:
+		return "quit"
+	case EndFileError:
+		return "error"
+	case EndFileRedirect:
+		return "redirect"
+	}
+	return ""
+}
+
+// Erros do backend que o player trata; o adaptador do libmpv traduz os dele para estes
+var (
+	ErrPropertyUnavailable = errors.New("propriedade indisponível")
+	ErrPropertyFormat      = errors.New("formato de propriedade não suportado")
+	ErrVoInitFailed        = errors.New("falha ao inicializar a saída de vídeo")
+)
+
+// EngineEvent é um evento do backend já decodificado
+// Não guarda ponteiros para memória do libmpv, então pode ser criado por fakes
+type EngineEvent struct {
+	ID            EngineEventID
+	Error         error
+	ReplyUserdata uint64
+	Property      *PropertyChange // preenchido em EnginePropertyChange
+	EndFile       *EndFile        // preenchido em EngineEnd
+	ClientMessage []string        // preenchido em EngineClientMessage (argumentos do script-message)
+}
+
+// PropertyChange é o novo valor de uma propriedade observada
+type PropertyChange struct {
+	Name   string
+	Format Format
+	Data   interface{}
+}
+
+// EndFile é o payload do fim de um arquivo
+type EndFile struct {
+	Reason EndReason
+	Error  error // preenchido com EndFileError
+}
+
+// EngineFactory cria e inicializa um Engine com opções aplicadas antes do Initialize
+type EngineFactory func(options ...Property) (Engine, error)
+
+var (
+	engineMu      sync.RWMutex
+	engineFactory EngineFactory
+)
+
+// RegisterEngine define o backend usado por New, NewWithBundle e pelas análises sem janela
+// O pacote player/mpvengine registra o libmpv no init; importe-o com _ no main
+func RegisterEngine(factory EngineFactory) {
+	engineMu.Lock()
+	defer engineMu.Unlock()
+
+	engineFactory = factory
+}
+
+// newEngine cria um Engine com o backend registrado
+func newEngine(options ...Property) (Engine, error) {
+	engineMu.RLock()
+	factory := engineFactory
+	engineMu.RUnlock()
+
+	if factory == nil {
+		return nil, fmt.Errorf("nenhum backend registrado (importe github.com/ThiagoFrag/Goanime-Player4k/player/mpvengine)")
+	}
+	return factory(options...)
+}
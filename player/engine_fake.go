@@ -0,0 +1,279 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EngineCall registra uma chamada feita ao FakeEngine
+type EngineCall struct {
+	Kind  string // "option", "property", "command", "observe"
+	Name  string
+	Value string
+	Args  []string
+}
+
+// FakeEngine é um Engine em memória que grava tudo o que o Player envia
+// Usado em testes para verificar propriedades e comandos sem abrir janela
+type FakeEngine struct {
+	mu         sync.Mutex
+	calls      []EngineCall
+	options    map[string]string
+	properties map[string]interface{}
+	events     chan *EngineEvent
+	wakeup     chan struct{}
+	terminated bool
+
+	// CommandHook, se definido, decide o erro retornado por Command
+	CommandHook func(cmd []string) error
+}
+
+// NewFakeEngine cria um FakeEngine vazio
+func NewFakeEngine() *FakeEngine {
+	return &FakeEngine{
+		options:    make(map[string]string),
+		properties: make(map[string]interface{}),
+		events:     make(chan *EngineEvent, 256),
+		wakeup:     make(chan struct{}, 1),
+	}
+}
+
+func (f *FakeEngine) SetOptionString(name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.options[name] = value
+	f.calls = append(f.calls, EngineCall{Kind: "option", Name: name, Value: value})
+	return nil
+}
+
+func (f *FakeEngine) SetPropertyString(name, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.properties[name] = value
+	f.calls = append(f.calls, EngineCall{Kind: "property", Name: name, Value: value})
+	return nil
+}
+
+func (f *FakeEngine) SetProperty(name string, _ Format, data interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.properties[name] = data
+	f.calls = append(f.calls, EngineCall{Kind: "property", Name: name, Value: fmt.Sprint(data)})
+	return nil
+}
+
+func (f *FakeEngine) GetProperty(name string, format Format) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	val, ok := f.properties[name]
+	if !ok {
+		return nil, ErrPropertyUnavailable
+	}
+	return convertFakeValue(val, format)
+}
+
+func (f *FakeEngine) Command(cmd []string) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, EngineCall{Kind: "command", Args: append([]string(nil), cmd...)})
+	// change-list append atualiza a lista como o mpv (ex: glsl-shaders)
+	if len(cmd) == 4 && cmd[0] == "change-list" && cmd[2] == "append" {
+		list := fmt.Sprint(f.properties[cmd[1]])
+		if f.properties[cmd[1]] == nil || list == "" {
+			f.properties[cmd[1]] = cmd[3]
+		} else {
+			f.properties[cmd[1]] = list + string(os.PathListSeparator) + cmd[3]
+		}
+	}
+	hook := f.CommandHook
+	f.mu.Unlock()
+
+	if hook != nil {
+		return hook(cmd)
+	}
+	return nil
+}
+
+func (f *FakeEngine) ObserveProperty(id uint64, name string, _ Format) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, EngineCall{Kind: "observe", Name: name, Value: strconv.FormatUint(id, 10)})
+	return nil
+}
+
+// WaitEvent devolve o próximo evento enfileirado por PushEvent
+func (f *FakeEngine) WaitEvent(timeout float64) *EngineEvent {
+	if timeout <= 0 {
+		select {
+		case ev := <-f.events:
+			return ev
+		default:
+			return nil
+		}
+	}
+
+	select {
+	case ev := <-f.events:
+		return ev
+	case <-f.wakeup:
+		return nil
+	case <-time.After(time.Duration(timeout * float64(time.Second))):
+		return nil
+	}
+}
+
+// Wakeup faz o WaitEvent em andamento (ou o próximo) retornar nil
+func (f *FakeEngine) Wakeup() {
+	select {
+	case f.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (f *FakeEngine) TerminateDestroy() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.terminated = true
+}
+
+// --- Helpers para testes ---
+
+// PushEvent enfileira um evento para o próximo WaitEvent
+func (f *FakeEngine) PushEvent(ev *EngineEvent) {
+	f.events <- ev
+}
+
+// SetValue define o valor que GetProperty vai retornar (sem registrar chamada)
+func (f *FakeEngine) SetValue(name string, value interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.properties[name] = value
+}
+
+// Property retorna o último valor enviado para uma propriedade
+func (f *FakeEngine) Property(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	val, ok := f.properties[name]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(val), true
+}
+
+// Option retorna o último valor enviado para uma opção
+func (f *FakeEngine) Option(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	val, ok := f.options[name]
+	return val, ok
+}
+
+// Calls retorna todas as chamadas registradas, em ordem
+func (f *FakeEngine) Calls() []EngineCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]EngineCall(nil), f.calls...)
+}
+
+// PropertyWrites retorna a sequência de escritas em propriedades (nome=valor)
+func (f *FakeEngine) PropertyWrites() []EngineCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var writes []EngineCall
+	for _, c := range f.calls {
+		if c.Kind == "property" {
+			writes = append(writes, c)
+		}
+	}
+	return writes
+}
+
+// Commands retorna os comandos enviados, em ordem
+func (f *FakeEngine) Commands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var cmds [][]string
+	for _, c := range f.calls {
+		if c.Kind == "command" {
+			cmds = append(cmds, c.Args)
+		}
+	}
+	return cmds
+}
+
+// Reset limpa o histórico de chamadas (mantém os valores das propriedades)
+func (f *FakeEngine) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+// Terminated indica se TerminateDestroy foi chamado
+func (f *FakeEngine) Terminated() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.terminated
+}
+
+// convertFakeValue converte um valor guardado para o formato pedido
+func convertFakeValue(val interface{}, format Format) (interface{}, error) {
+	s := fmt.Sprint(val)
+
+	switch format {
+	case FormatString, FormatOsdString:
+		return s, nil
+	case FormatFlag:
+		switch v := val.(type) {
+		case bool:
+			return v, nil
+		default:
+			return s == "yes" || s == "true" || s == "1", nil
+		}
+	case FormatInt64:
+		switch v := val.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case float64:
+			return int64(v), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrPropertyFormat
+		}
+		return n, nil
+	case FormatDouble:
+		switch v := val.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, ErrPropertyFormat
+		}
+		return n, nil
+	}
+	return nil, ErrPropertyFormat
+}
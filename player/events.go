@@ -0,0 +1,196 @@
+package player
+
+import (
+	"context"
+	"sync"
+)
+
+// EventType identifica o tipo de um evento do player
+// Os valores também são os nomes das notificações do servidor JSON-RPC
+type EventType string
+
+const (
+	EventFileLoaded       EventType = "fileLoaded"
+	EventEnded            EventType = "ended"
+	EventTimeUpdate       EventType = "timeUpdate"
+	EventStateChanged     EventType = "stateChanged"
+	EventModeChanged      EventType = "modeChanged"
+	EventTrackListChanged EventType = "trackListChanged"
+	EventBuffering        EventType = "buffering"
+	EventPlaylistChanged  EventType = "playlistChanged"
+	EventSkipSegments     EventType = "skipSegments"
+	EventSegment          EventType = "segment"
+	EventIntroAnalysis    EventType = "introAnalysis"
+	EventProfile          EventType = "profile"
+	EventScriptRequest    EventType = "scriptRequest"
+	EventError            EventType = "error"
+)
+
+// Event é um evento publicado pelo player (use um type switch nos tipos *Event)
+type Event interface {
+	Type() EventType
+}
+
+// FileLoadedEvent é publicado quando o MPV termina de abrir o arquivo
+type FileLoadedEvent struct {
+	Path     string  `json:"path"`
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"`
+	Width    int64   `json:"width"`
+	Height   int64   `json:"height"`
+}
+
+// EndedEvent é publicado quando o arquivo termina ou é interrompido
+type EndedEvent struct {
+	Reason string `json:"reason"` // "eof", "stop", "quit", "error" ou "redirect"
+	Error  string `json:"error,omitempty"`
+}
+
+// TimeUpdateEvent traz a posição atual da reprodução
+type TimeUpdateEvent struct {
+	Position float64 `json:"position"`
+	Duration float64 `json:"duration"`
+}
+
+// StateChangedEvent é publicado quando a reprodução muda de estado (veja State)
+type StateChangedEvent struct {
+	State    PlayerState `json:"state"`
+	Previous PlayerState `json:"previous"`
+}
+
+// ModeChangedEvent é publicado quando o modo ou a cadeia de shaders mudam
+type ModeChangedEvent struct {
+	ModeChange
+}
+
+// TrackListChangedEvent traz a lista de faixas atualizada
+type TrackListChangedEvent struct {
+	Tracks []Track `json:"tracks"`
+}
+
+// PlaylistChangedEvent traz a playlist atualizada (itens adicionados, removidos ou troca de episódio)
+type PlaylistChangedEvent struct {
+	Entries  []PlaylistEntry `json:"entries"`
+	Position int             `json:"position"`
+}
+
+// SkipSegmentsEvent traz os trechos para pular do arquivo atual (capítulos + externos)
+type SkipSegmentsEvent struct {
+	Segments []SkipSegment `json:"segments"`
+}
+
+// SegmentEvent é publicado ao entrar, sair ou pular um trecho (abertura, encerramento...)
+type SegmentEvent struct {
+	Action  string      `json:"action"` // "enter", "leave" ou "skipped"
+	Segment SkipSegment `json:"segment"`
+	Mode    SkipMode    `json:"mode,omitempty"`
+}
+
+// IntroAnalysisEvent é publicado quando a análise de aberturas em segundo plano termina
+type IntroAnalysisEvent struct {
+	Series   string `json:"series"`
+	Episodes int    `json:"episodes"`
+	Found    int    `json:"found"` // episódios com abertura ou encerramento encontrado
+	Error    string `json:"error,omitempty"`
+}
+
+// ProfileEvent é publicado quando o perfil da série é aplicado ou muda durante a reprodução
+type ProfileEvent struct {
+	Action  string        `json:"action"` // "applied" ou "updated"
+	Profile SeriesProfile `json:"profile"`
+}
+
+// ScriptRequestEvent é publicado quando o goanime.lua pede algo ao player
+// O pedido já foi atendido quando o evento chega; Error diz se falhou
+type ScriptRequestEvent struct {
+	Request ScriptRequest   `json:"request"`
+	Mode    PerformanceMode `json:"mode,omitempty"` // em switch-mode
+	Error   string          `json:"error,omitempty"`
+}
+
+// BufferingEvent indica que a reprodução parou (ou voltou) esperando o cache
+type BufferingEvent struct {
+	Buffering bool `json:"buffering"`
+}
+
+// ErrorEvent é publicado quando algo falha (carregar arquivo, saída de vídeo...)
+type ErrorEvent struct {
+	Err     error  `json:"-"`
+	Message string `json:"message"`
+}
+
+func (FileLoadedEvent) Type() EventType       { return EventFileLoaded }
+func (EndedEvent) Type() EventType            { return EventEnded }
+func (TimeUpdateEvent) Type() EventType       { return EventTimeUpdate }
+func (StateChangedEvent) Type() EventType     { return EventStateChanged }
+func (ModeChangedEvent) Type() EventType      { return EventModeChanged }
+func (TrackListChangedEvent) Type() EventType { return EventTrackListChanged }
+func (PlaylistChangedEvent) Type() EventType  { return EventPlaylistChanged }
+func (SkipSegmentsEvent) Type() EventType     { return EventSkipSegments }
+func (SegmentEvent) Type() EventType          { return EventSegment }
+func (IntroAnalysisEvent) Type() EventType    { return EventIntroAnalysis }
+func (ProfileEvent) Type() EventType          { return EventProfile }
+func (ScriptRequestEvent) Type() EventType    { return EventScriptRequest }
+func (BufferingEvent) Type() EventType        { return EventBuffering }
+func (ErrorEvent) Type() EventType            { return EventError }
+
+// eventBufferSize é a fila de cada assinante; eventos além dela são descartados
+const eventBufferSize = 64
+
+// eventBus distribui eventos para vários assinantes sem nunca bloquear quem publica
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// subscribe cria um canal que recebe eventos até o contexto ser cancelado
+func (b *eventBus) subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, eventBufferSize)
+
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan Event]struct{})
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
+
+// publish entrega o evento a todos os assinantes
+// Assinante com a fila cheia perde o evento (o player nunca espera a GUI)
+func (b *eventBus) publish(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe retorna um canal com os eventos do player
+// O canal é fechado quando ctx é cancelado; assinantes lentos perdem eventos
+func (p *Player) Subscribe(ctx context.Context) <-chan Event {
+	return p.events.subscribe(ctx)
+}
+
+// emit publica um evento; nunca deve ser chamado com p.mu travado
+func (p *Player) emit(ev Event) {
+	p.events.publish(ev)
+}
+
+// emitError publica um ErrorEvent
+func (p *Player) emitError(err error) {
+	p.emit(ErrorEvent{Err: err, Message: err.Error()})
+}
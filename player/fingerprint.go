@@ -0,0 +1,324 @@
+package player
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+)
+
+// Parâmetros da impressão digital (os mesmos do chromaprint)
+const (
+	fingerprintRate  = 11025 // taxa de amostragem analisada (mono)
+	fingerprintFrame = 4096  // amostras por FFT
+	fingerprintHop   = 1365  // avanço entre frames (2/3 de sobreposição)
+)
+
+// FingerprintStep é a duração coberta por cada valor da impressão digital (~0,124s)
+const FingerprintStep = float64(fingerprintHop) / fingerprintRate
+
+// Comparação entre impressões digitais
+const (
+	minFrameEnergy = 1.0 // abaixo disso o frame é silêncio (não casa com nada)
+	maxBitErrors   = 10  // bits diferentes (de 32) para dois frames serem "iguais"
+	maxGapFrames   = 4   // frames diferentes tolerados dentro de um trecho (~0,5s)
+)
+
+// Fingerprint é a impressão digital de um áudio: um hash de 32 bits por frame
+// Cada hash compara a energia das 12 notas (chroma) entre si, então o mesmo
+// trecho de música gera hashes parecidos mesmo com volume ou codec diferentes
+type Fingerprint []uint32
+
+// ChromaFingerprint calcula a impressão digital de amostras mono em [-1, 1]
+func ChromaFingerprint(samples []float32, rate int) Fingerprint {
+	if rate != fingerprintRate {
+		samples = resample(samples, rate, fingerprintRate)
+	}
+	n := (len(samples)-fingerprintFrame)/fingerprintHop + 1
+	if n <= 0 {
+		return nil
+	}
+
+	window := hannWindow(fingerprintFrame)
+	notes, weights := chromaBins(fingerprintFrame, fingerprintRate)
+	fft := newFFT(fingerprintFrame)
+	re := make([]float64, fingerprintFrame)
+	im := make([]float64, fingerprintFrame)
+
+	fp := make(Fingerprint, n)
+	for f := range fp {
+		offset := f * fingerprintHop
+		for i := range re {
+			re[i] = float64(samples[offset+i]) * window[i]
+			im[i] = 0
+		}
+		fft.transform(re, im)
+
+		var chroma [12]float64
+		energy := 0.0
+		for k := 1; k < fingerprintFrame/2; k++ {
+			note := notes[k]
+			if note < 0 {
+				continue
+			}
+			e := re[k]*re[k] + im[k]*im[k]
+			chroma[note] += e * weights[k]
+			energy += e
+		}
+		if energy >= minFrameEnergy {
+			fp[f] = chromaHash(chroma)
+		}
+	}
+	return fp
+}
+
+// chromaHash resume as 12 notas em 32 bits (quais notas são mais fortes que quais)
+func chromaHash(c [12]float64) uint32 {
+	var h uint32
+	for i := 0; i < 12; i++ {
+		if c[i] > c[(i+1)%12] {
+			h |= 1 << i
+		}
+		if c[i] > c[(i+3)%12] {
+			h |= 1 << (12 + i)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		if c[i] > c[(i+4)%12] {
+			h |= 1 << (24 + i)
+		}
+	}
+	return h
+}
+
+// chromaBins mapeia cada bin da FFT para uma nota (0 = Lá, -1 = fora de 28Hz–3520Hz)
+// e dá o peso do bin: 1 / bins do semitom, para que notas agudas (com mais bins)
+// não pesem mais que as graves
+func chromaBins(size, rate int) ([]int, []float64) {
+	notes := make([]int, size/2)
+	semitones := make([]int, size/2)
+	count := make(map[int]int)
+	for k := range notes {
+		freq := float64(k) * float64(rate) / float64(size)
+		if freq < 28 || freq > 3520 {
+			notes[k] = -1
+			continue
+		}
+		semitone := int(math.Round(12 * math.Log2(freq/440)))
+		notes[k] = ((semitone % 12) + 12) % 12
+		semitones[k] = semitone
+		count[semitone]++
+	}
+
+	weights := make([]float64, size/2)
+	for k := range weights {
+		if notes[k] >= 0 {
+			weights[k] = 1 / float64(count[semitones[k]])
+		}
+	}
+	return notes, weights
+}
+
+// hannWindow suaviza as bordas de cada frame antes da FFT
+func hannWindow(size int) []float64 {
+	w := make([]float64, size)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size-1))
+	}
+	return w
+}
+
+// resample converte a taxa de amostragem por interpolação linear
+// O MPV já entrega 11025Hz; isto só é usado com WAVs de outras fontes
+func resample(samples []float32, from, to int) []float32 {
+	if from <= 0 || len(samples) == 0 {
+		return nil
+	}
+	n := int(int64(len(samples)) * int64(to) / int64(from))
+	out := make([]float32, n)
+	ratio := float64(from) / float64(to)
+	for i := range out {
+		pos := float64(i) * ratio
+		j := int(pos)
+		if j+1 >= len(samples) {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+		frac := float32(pos - float64(j))
+		out[i] = samples[j]*(1-frac) + samples[j+1]*frac
+	}
+	return out
+}
+
+// fftPlan é uma FFT radix-2 com a tabela de senos/cossenos pronta
+type fftPlan struct {
+	size   int
+	cos    []float64
+	sin    []float64
+	invert []int
+}
+
+// newFFT prepara uma FFT de size pontos (potência de 2)
+func newFFT(size int) *fftPlan {
+	p := &fftPlan{size: size, cos: make([]float64, size/2), sin: make([]float64, size/2), invert: make([]int, size)}
+	for k := range p.cos {
+		angle := -2 * math.Pi * float64(k) / float64(size)
+		p.cos[k], p.sin[k] = math.Cos(angle), math.Sin(angle)
+	}
+	shift := 32 - bits.Len(uint(size-1))
+	for i := range p.invert {
+		p.invert[i] = int(bits.Reverse32(uint32(i)) >> shift)
+	}
+	return p
+}
+
+// transform calcula a FFT no lugar (re/im com size elementos)
+func (p *fftPlan) transform(re, im []float64) {
+	for i, j := range p.invert {
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+	for size := 2; size <= p.size; size <<= 1 {
+		half, step := size/2, p.size/size
+		for start := 0; start < p.size; start += size {
+			for k := 0; k < half; k++ {
+				wr, wi := p.cos[k*step], p.sin[k*step]
+				a, b := start+k, start+k+half
+				tr := re[b]*wr - im[b]*wi
+				ti := re[b]*wi + im[b]*wr
+				re[b], im[b] = re[a]-tr, im[a]-ti
+				re[a], im[a] = re[a]+tr, im[a]+ti
+			}
+		}
+	}
+}
+
+// --- Comparação ---
+
+// fingerprintMatch é um trecho em comum entre duas impressões digitais (em frames)
+type fingerprintMatch struct {
+	StartA, StartB, Length int
+}
+
+// findCommonSegment procura o maior trecho que se repete nas duas impressões digitais
+// Testa todos os deslocamentos; dentro de um trecho, até maxGapFrames frames seguidos
+// podem diferir e pelo menos metade precisa casar
+func findCommonSegment(a, b Fingerprint, minFrames int) (fingerprintMatch, bool) {
+	var best fingerprintMatch
+	if minFrames <= 0 {
+		minFrames = 1
+	}
+
+	for shift := -(len(b) - minFrames); shift <= len(a)-minFrames; shift++ {
+		// a[i] é comparado com b[i-shift]
+		from, to := max(0, shift), min(len(a), len(b)+shift)
+		runStart, lastGood, good := -1, -1, 0
+
+		closeRun := func() {
+			length := lastGood - runStart + 1
+			if length >= minFrames && good*2 >= length && length > best.Length {
+				best = fingerprintMatch{StartA: runStart, StartB: runStart - shift, Length: length}
+			}
+			runStart, good = -1, 0
+		}
+
+		for i := from; i < to; i++ {
+			x, y := a[i], b[i-shift]
+			if x == 0 || y == 0 || bits.OnesCount32(x^y) > maxBitErrors {
+				if runStart >= 0 && i-lastGood > maxGapFrames {
+					closeRun()
+				}
+				continue
+			}
+			if runStart < 0 {
+				runStart = i
+			}
+			lastGood = i
+			good++
+		}
+		if runStart >= 0 {
+			closeRun()
+		}
+	}
+	return best, best.Length > 0
+}
+
+// --- WAV ---
+
+// ReadWAV lê um WAV PCM 16 bits ou float 32 bits e mistura os canais em mono
+// Aceita o cabeçalho sem tamanho final que o ao=pcm deixa quando é interrompido
+func ReadWAV(path string) ([]float32, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("%s: não é um arquivo WAV", path)
+	}
+
+	var format, channels, bitsPerSample uint16
+	var rate uint32
+	var pcm []byte
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := pos + 8
+		if size < 0 || body+size > len(data) {
+			size = len(data) - body
+		}
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, fmt.Errorf("%s: cabeçalho fmt inválido", path)
+			}
+			format = binary.LittleEndian.Uint16(data[body:])
+			channels = binary.LittleEndian.Uint16(data[body+2:])
+			rate = binary.LittleEndian.Uint32(data[body+4:])
+			bitsPerSample = binary.LittleEndian.Uint16(data[body+14:])
+			if format == 0xFFFE && size >= 26 {
+				// WAVE_FORMAT_EXTENSIBLE: o formato real está no subformato
+				format = binary.LittleEndian.Uint16(data[body+24:])
+			}
+		case "data":
+			pcm = data[body : body+size]
+		}
+		pos = body + size + size%2
+	}
+
+	if channels == 0 || rate == 0 || pcm == nil {
+		return nil, 0, fmt.Errorf("%s: WAV sem áudio", path)
+	}
+
+	var samples []float32
+	switch {
+	case format == 1 && bitsPerSample == 16:
+		frames := len(pcm) / (2 * int(channels))
+		samples = make([]float32, frames)
+		for i := range samples {
+			sum := 0.0
+			for c := 0; c < int(channels); c++ {
+				off := (i*int(channels) + c) * 2
+				sum += float64(int16(binary.LittleEndian.Uint16(pcm[off:]))) / 32768
+			}
+			samples[i] = float32(sum / float64(channels))
+		}
+	case format == 3 && bitsPerSample == 32:
+		frames := len(pcm) / (4 * int(channels))
+		samples = make([]float32, frames)
+		for i := range samples {
+			sum := float32(0)
+			for c := 0; c < int(channels); c++ {
+				off := (i*int(channels) + c) * 4
+				sum += math.Float32frombits(binary.LittleEndian.Uint32(pcm[off:]))
+			}
+			samples[i] = sum / float32(channels)
+		}
+	default:
+		return nil, 0, fmt.Errorf("%s: formato WAV não suportado (formato %d, %d bits)", path, format, bitsPerSample)
+	}
+	return samples, int(rate), nil
+}
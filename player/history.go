@@ -0,0 +1,433 @@
+package player
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// historySaveInterval é o intervalo entre gravações do histórico durante a reprodução
+const historySaveInterval = 10 * time.Second
+
+// maxHistoryEntries limita o tamanho do history.json (os mais antigos saem primeiro)
+const maxHistoryEntries = 2000
+
+// WatchEntry é a posição salva de um arquivo ou episódio
+type WatchEntry struct {
+	Key       string    `json:"key"` // veja MediaKey
+	Path      string    `json:"path"`
+	Title     string    `json:"title,omitempty"`
+	Series    string    `json:"series,omitempty"`
+	Season    int       `json:"season,omitempty"`
+	Episode   int       `json:"episode,omitempty"`
+	Position  float64   `json:"position"`
+	Duration  float64   `json:"duration"`
+	Watched   bool      `json:"watched"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WatchHistory guarda onde o usuário parou em cada episódio (history.json)
+type WatchHistory struct {
+	// WatchedFraction marca como assistido a partir desta fração da duração (0.9 = 90%)
+	WatchedFraction float64
+	// UseEndingChapter marca como assistido no início do capítulo de ED, se existir
+	UseEndingChapter bool
+	// MinResume ignora posições muito perto do início ou do fim (segundos)
+	MinResume float64
+
+	mu      sync.Mutex
+	path    string
+	entries map[string]*WatchEntry
+	dirty   bool
+}
+
+// DefaultWatchHistoryPath retorna o caminho do history.json na pasta de configuração
+func DefaultWatchHistoryPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// OpenWatchHistory lê o histórico (vazio se o arquivo não existir)
+func OpenWatchHistory(path string) (*WatchHistory, error) {
+	h := &WatchHistory{
+		WatchedFraction:  0.9,
+		UseEndingChapter: true,
+		MinResume:        10,
+		path:             path,
+		entries:          make(map[string]*WatchEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*WatchEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, e := range list {
+		if e.Key != "" {
+			h.entries[e.Key] = e
+		}
+	}
+	return h, nil
+}
+
+// OpenDefaultWatchHistory abre o history.json da pasta de configuração
+// O limite de "assistido" vem do watchedThreshold do player4k.json
+func OpenDefaultWatchHistory() (*WatchHistory, error) {
+	path, err := DefaultWatchHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	h, err := OpenWatchHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg, err := LoadUserConfig(); err == nil && cfg.WatchedThreshold > 0 && cfg.WatchedThreshold <= 1 {
+		h.WatchedFraction = cfg.WatchedThreshold
+	}
+	return h, nil
+}
+
+// Get retorna a entrada de uma chave
+func (h *WatchHistory) Get(key string) (WatchEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e, ok := h.entries[key]
+	if !ok {
+		return WatchEntry{}, false
+	}
+	return *e, true
+}
+
+// Entries retorna todas as entradas, da mais recente para a mais antiga
+func (h *WatchHistory) Entries() []WatchEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	list := make([]WatchEntry, 0, len(h.entries))
+	for _, e := range h.entries {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
+	return list
+}
+
+// Record grava (em memória) a posição de uma entrada; Save persiste
+// Um episódio assistido continua assistido mesmo se for revisto do início
+func (h *WatchHistory) Record(e WatchEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if prev, ok := h.entries[e.Key]; ok && prev.Watched {
+		e.Watched = true
+	}
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = time.Now()
+	}
+	h.entries[e.Key] = &e
+	h.dirty = true
+}
+
+// Forget remove uma entrada
+func (h *WatchHistory) Forget(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.entries[key]; ok {
+		delete(h.entries, key)
+		h.dirty = true
+	}
+}
+
+// ResumePosition retorna de onde continuar (ok é false se assistido ou perto das pontas)
+func (h *WatchHistory) ResumePosition(key string) (float64, bool) {
+	e, ok := h.Get(key)
+	if !ok || e.Watched || e.Position < h.MinResume {
+		return 0, false
+	}
+	if e.Duration > 0 && e.Duration-e.Position < h.MinResume {
+		return 0, false
+	}
+	return e.Position, true
+}
+
+// IsWatched aplica o limite de "assistido" a uma posição
+// endingStart é o início do capítulo de ED (0 se não houver)
+func (h *WatchHistory) IsWatched(position, duration, endingStart float64) bool {
+	if duration <= 0 {
+		return false
+	}
+	if h.UseEndingChapter && endingStart > duration/2 && position >= endingStart {
+		return true
+	}
+	return h.WatchedFraction > 0 && position >= duration*h.WatchedFraction
+}
+
+// Save grava o history.json se algo mudou
+func (h *WatchHistory) Save() error {
+	h.mu.Lock()
+	if !h.dirty {
+		h.mu.Unlock()
+		return nil
+	}
+	list := make([]*WatchEntry, 0, len(h.entries))
+	for _, e := range h.entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
+	if len(list) > maxHistoryEntries {
+		for _, e := range list[maxHistoryEntries:] {
+			delete(h.entries, e.Key)
+		}
+		list = list[:maxHistoryEntries]
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	h.dirty = false
+	h.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(h.path, data)
+}
+
+// MediaKey é a identidade estável de uma mídia no histórico:
+//   - episódio reconhecido no nome: série + temporada + episódio (vale para qualquer release)
+//   - URL: endereço sem query/fragmento (tokens de acesso mudam a cada sessão)
+//   - arquivo local: hash do caminho absoluto
+func MediaKey(path string) string {
+	clean := path
+	if strings.Contains(path, "://") {
+		clean = stripURLTokens(path)
+	}
+	if info := ParseReleasePath(clean); info.Series != "" && info.Episode > 0 && !info.Extra {
+		season := info.Season
+		if season == 0 {
+			season = 1
+		}
+		return fmt.Sprintf("ep:%s:s%02de%04d", strings.ToLower(info.Series), season, info.Episode)
+	}
+	if clean != path {
+		return "url:" + clean
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha1.Sum([]byte(path))
+	return "file:" + hex.EncodeToString(sum[:8])
+}
+
+// stripURLTokens tira usuário, query e fragmento de uma URL
+func stripURLTokens(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.SplitN(strings.SplitN(raw, "?", 2)[0], "#", 2)[0]
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// endingChapterStart procura o capítulo de encerramento (ED, Ending, Credits...)
+func endingChapterStart(chapters []Chapter) float64 {
+	for _, c := range chapters {
+		title := strings.ToLower(strings.TrimSpace(c.Title))
+		if title == "ed" || containsAny(title, "ending", "credits", "créditos", "outro", "encerramento") {
+			return c.Time
+		}
+	}
+	return 0
+}
+
+// --- Integração com o Player ---
+
+// mediaHistory é o arquivo aberto do ponto de vista do histórico
+type mediaHistory struct {
+	entry       WatchEntry
+	endingStart float64
+	lastSave    time.Time
+}
+
+// SetWatchHistory liga o histórico de reprodução (nil desliga)
+func (p *Player) SetWatchHistory(h *WatchHistory) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.history = h
+}
+
+// WatchHistory retorna o histórico em uso (nil se desligado)
+func (p *Player) WatchHistory() *WatchHistory {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.history
+}
+
+// SetAutoResume faz cada arquivo aberto continuar de onde parou
+func (p *Player) SetAutoResume(enable bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.autoResume = enable
+}
+
+// GetResumePosition retorna a posição salva do arquivo atual (0 se não houver)
+func (p *Player) GetResumePosition() float64 {
+	p.mu.Lock()
+	h, key := p.history, p.media.entry.Key
+	p.mu.Unlock()
+
+	if h == nil || key == "" {
+		return 0
+	}
+	pos, _ := h.ResumePosition(key)
+	return pos
+}
+
+// Resume volta para a posição salva do arquivo atual
+func (p *Player) Resume() error {
+	pos := p.GetResumePosition()
+	if pos <= 0 {
+		return fmt.Errorf("nenhuma posição salva para este arquivo")
+	}
+	p.Seek(pos)
+	fmt.Printf("⏯️ Continuando de %s\n", formatClock(pos))
+	return nil
+}
+
+// MarkWatched marca o arquivo atual como assistido, sem esperar o fim
+func (p *Player) MarkWatched() error {
+	p.mu.Lock()
+	h, media := p.history, p.media
+	if p.snapshot.Duration > 0 {
+		media.entry.Duration = p.snapshot.Duration
+	}
+	media.entry.Position = p.snapshot.Position
+	p.mu.Unlock()
+
+	if h == nil {
+		return fmt.Errorf("histórico desligado")
+	}
+	if media.entry.Key == "" {
+		return fmt.Errorf("nenhum arquivo aberto")
+	}
+
+	e := media.entry
+	e.Watched = true
+	h.Record(e)
+	if err := h.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Episódio assistido: %s\n", e.Title)
+	return nil
+}
+
+// startHistory começa a acompanhar o arquivo que acabou de abrir
+func (p *Player) startHistory(loaded FileLoadedEvent) {
+	p.mu.Lock()
+	h, autoResume := p.history, p.autoResume
+	p.mu.Unlock()
+
+	if h == nil || loaded.Path == "" {
+		return
+	}
+
+	info := ParseReleasePath(loaded.Path)
+	chapters, _ := parseChapterList(p.getString("chapter-list"))
+	media := mediaHistory{
+		entry: WatchEntry{
+			Key:      MediaKey(loaded.Path),
+			Path:     loaded.Path,
+			Title:    CleanTitle(loaded.Path),
+			Series:   info.Series,
+			Season:   info.Season,
+			Episode:  info.Episode,
+			Duration: loaded.Duration,
+		},
+		endingStart: endingChapterStart(chapters),
+		lastSave:    time.Now(),
+	}
+	if strings.Contains(loaded.Path, "://") {
+		media.entry.Path = stripURLTokens(loaded.Path)
+	}
+
+	p.mu.Lock()
+	p.media = media
+	p.mu.Unlock()
+
+	if autoResume {
+		p.Resume()
+	}
+}
+
+// tickHistory grava a posição periodicamente (chamado pelo loop de eventos)
+func (p *Player) tickHistory(now time.Time) {
+	p.mu.Lock()
+	if p.history == nil || p.media.entry.Key == "" || now.Sub(p.media.lastSave) < historySaveInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.media.lastSave = now
+	p.mu.Unlock()
+
+	p.recordHistory(false, false)
+}
+
+// recordHistory grava a posição atual; final encerra o acompanhamento do arquivo
+// ended indica que o arquivo chegou ao fim (conta como assistido)
+func (p *Player) recordHistory(final, ended bool) {
+	p.mu.Lock()
+	h, media := p.history, p.media
+	if final {
+		p.media = mediaHistory{}
+	}
+	eof := ended || p.snapshot.EOFReached
+	if p.snapshot.Duration > 0 {
+		media.entry.Duration = p.snapshot.Duration
+	}
+	p.mu.Unlock()
+
+	if h == nil || media.entry.Key == "" || media.entry.Position <= 0 {
+		return
+	}
+
+	e := media.entry
+	e.Watched = eof || h.IsWatched(e.Position, e.Duration, media.endingStart)
+	h.Record(e)
+	if err := h.Save(); err != nil {
+		fmt.Printf("⚠️ Não foi possível salvar o histórico: %v\n", err)
+	}
+	if e.Watched && final {
+		fmt.Printf("✓ Episódio assistido: %s\n", e.Title)
+	}
+}
+
+// formatClock formata segundos como hh:mm:ss
+func formatClock(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,480 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AudioClip é um trecho de áudio mono extraído de um episódio
+type AudioClip struct {
+	Samples  []float32
+	Rate     int
+	Start    float64 // posição da primeira amostra no episódio (segundos)
+	Duration float64 // duração do episódio inteiro
+}
+
+// AudioExtractor extrai length segundos de áudio a partir de start
+// start negativo conta a partir do fim do arquivo (-360 = últimos 6 minutos)
+type AudioExtractor func(ctx context.Context, path string, start, length float64) (*AudioClip, error)
+
+// IntroOptions configura a detecção de abertura/encerramento por áudio
+type IntroOptions struct {
+	Window    float64        // segundos analisados no começo (OP) e no fim (ED) de cada episódio
+	MinLength float64        // duração mínima do trecho repetido (segundos)
+	Extract   AudioExtractor // padrão: ExtractAudio (MPV com ao=pcm)
+
+	// Progress, se definido, é chamado antes de extrair cada episódio
+	Progress func(done, total int, path string)
+}
+
+// DefaultIntroOptions retorna as opções padrão da análise
+func DefaultIntroOptions() IntroOptions {
+	return IntroOptions{
+		Window:    360,
+		MinLength: 20,
+		Extract:   ExtractAudio,
+	}
+}
+
+// EpisodeIntro são os trechos encontrados em um episódio
+type EpisodeIntro struct {
+	Path     string        `json:"path"`
+	Key      string        `json:"key"` // veja MediaKey
+	Segments []SkipSegment `json:"segments"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// IntroAnalysis é o resultado da análise de vários episódios
+type IntroAnalysis struct {
+	Series   string         `json:"series"`
+	Episodes []EpisodeIntro `json:"episodes"`
+}
+
+// Found conta os episódios em que algum trecho foi encontrado
+func (a *IntroAnalysis) Found() int {
+	n := 0
+	for _, e := range a.Episodes {
+		if len(e.Segments) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// episodePrints são as impressões digitais do começo e do fim de um episódio
+type episodePrints struct {
+	head, tail           Fingerprint
+	headStart, tailStart float64
+}
+
+// AnalyzeIntros compara o áudio de episódios da mesma série e encontra a abertura
+// (trecho repetido no começo) e o encerramento (repetido no fim) de cada um
+// Cada episódio é comparado com os vizinhos na ordem informada (use SortEpisodes)
+func AnalyzeIntros(ctx context.Context, paths []string, opts IntroOptions) (*IntroAnalysis, error) {
+	if len(paths) < 2 {
+		return nil, fmt.Errorf("são necessários pelo menos 2 episódios da mesma série")
+	}
+	if opts.Extract == nil {
+		opts.Extract = ExtractAudio
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultIntroOptions().Window
+	}
+	minFrames := int(opts.MinLength / FingerprintStep)
+
+	result := &IntroAnalysis{Series: ParseReleasePath(paths[0]).Series}
+	prints := make([]*episodePrints, len(paths))
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if opts.Progress != nil {
+			opts.Progress(i, len(paths), path)
+		}
+
+		result.Episodes = append(result.Episodes, EpisodeIntro{Path: path, Key: MediaKey(path), Segments: []SkipSegment{}})
+		ep, err := fingerprintEpisode(ctx, path, opts)
+		if err != nil {
+			result.Episodes[i].Error = err.Error()
+			continue
+		}
+		prints[i] = ep
+	}
+
+	for i, ep := range prints {
+		if ep == nil {
+			continue
+		}
+		// Vizinhos primeiro (i+1, i-1, i+2, i-2...): episódios próximos costumam ter a mesma abertura
+		for _, j := range neighbours(i, len(prints)) {
+			if prints[j] == nil {
+				continue
+			}
+			if m, ok := findCommonSegment(ep.head, prints[j].head, minFrames); ok {
+				result.Episodes[i].Segments = append(result.Episodes[i].Segments, matchSegment(SegmentOpening, ep.headStart, m))
+				break
+			}
+		}
+		for _, j := range neighbours(i, len(prints)) {
+			if prints[j] == nil {
+				continue
+			}
+			if m, ok := findCommonSegment(ep.tail, prints[j].tail, minFrames); ok {
+				result.Episodes[i].Segments = append(result.Episodes[i].Segments, matchSegment(SegmentEnding, ep.tailStart, m))
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// fingerprintEpisode extrai e calcula as impressões digitais do começo e do fim
+func fingerprintEpisode(ctx context.Context, path string, opts IntroOptions) (*episodePrints, error) {
+	head, err := opts.Extract(ctx, path, 0, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := opts.Extract(ctx, path, -opts.Window, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	return &episodePrints{
+		head:      ChromaFingerprint(head.Samples, head.Rate),
+		tail:      ChromaFingerprint(tail.Samples, tail.Rate),
+		headStart: head.Start,
+		tailStart: tail.Start,
+	}, nil
+}
+
+// neighbours lista os outros episódios do mais próximo ao mais distante (no máximo 4)
+func neighbours(i, n int) []int {
+	var list []int
+	for d := 1; d < n && len(list) < 4; d++ {
+		if i+d < n {
+			list = append(list, i+d)
+		}
+		if i-d >= 0 {
+			list = append(list, i-d)
+		}
+	}
+	return list
+}
+
+// matchSegment converte um trecho em frames para segundos no episódio
+func matchSegment(kind SegmentKind, clipStart float64, m fingerprintMatch) SkipSegment {
+	start := clipStart + float64(m.StartA)*FingerprintStep
+	end := clipStart + float64(m.StartA+m.Length)*FingerprintStep
+	return SkipSegment{
+		Kind:   kind,
+		Start:  math.Round(start*10) / 10,
+		End:    math.Round(end*10) / 10,
+		Source: "fingerprint",
+	}
+}
+
+// --- Extração de áudio pelo MPV ---
+
+// audioExtractOptions deixam o MPV sem vídeo, gravando o áudio em WAV mono de 11025Hz
+// O ao=pcm não espera o relógio: um trecho de 6 minutos sai em poucos segundos
+var audioExtractOptions = []Property{
+	{"vid", "no"},
+	{"vo", "null"},
+	{"ao", "pcm"},
+	{"ao-pcm-waveheader", "yes"},
+	{"audio-samplerate", strconv.Itoa(fingerprintRate)},
+	{"audio-channels", "mono"},
+	{"audio-format", "s16"},
+	{"force-window", "no"},
+	{"load-scripts", "no"},
+	{"osc", "no"},
+	{"input-terminal", "no"},
+	{"terminal", "no"},
+}
+
+// ExtractAudio extrai um trecho de áudio com o MPV (libavfilter faz a conversão para mono/11025Hz)
+func ExtractAudio(ctx context.Context, path string, start, length float64) (*AudioClip, error) {
+	tmp, err := os.CreateTemp("", "player4k-*.wav")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	options := append([]Property{
+		{"ao-pcm-file", tmp.Name()},
+		{"start", strconv.FormatFloat(start, 'f', 3, 64)},
+		{"length", strconv.FormatFloat(length, 'f', 3, 64)},
+	}, audioExtractOptions...)
+	engine, err := newEngine(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := waitAudioExtract(ctx, engine, path)
+	// Fechar o MPV fecha o ao=pcm, que grava o tamanho final no cabeçalho do WAV
+	engine.TerminateDestroy()
+	if err != nil {
+		return nil, err
+	}
+
+	samples, rate, err := ReadWAV(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	clipStart := start
+	if start < 0 {
+		clipStart = math.Max(0, duration+start)
+	}
+	return &AudioClip{Samples: samples, Rate: rate, Start: clipStart, Duration: duration}, nil
+}
+
+// waitAudioExtract abre o arquivo e espera o MPV chegar ao fim do trecho
+// Retorna a duração do arquivo
+func waitAudioExtract(ctx context.Context, engine Engine, path string) (float64, error) {
+	if err := engine.Command([]string{"loadfile", path}); err != nil {
+		return 0, fmt.Errorf("erro ao carregar arquivo: %w", err)
+	}
+
+	duration := 0.0
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		ev := engine.WaitEvent(0.5)
+		if ev == nil {
+			continue
+		}
+		switch ev.ID {
+		case EngineFileLoaded:
+			if val, err := engine.GetProperty("duration", FormatDouble); err == nil {
+				duration, _ = val.(float64)
+			}
+		case EngineEnd:
+			if ev.EndFile != nil && ev.EndFile.Reason == EndFileError {
+				if ev.EndFile.Error != nil {
+					return 0, fmt.Errorf("não foi possível extrair o áudio de %s: %w", path, ev.EndFile.Error)
+				}
+				return 0, fmt.Errorf("não foi possível extrair o áudio de %s", path)
+			}
+			return duration, nil
+		case EngineShutdown:
+			return duration, nil
+		}
+	}
+}
+
+// --- Cache por série ---
+
+// SeriesIntros são os trechos encontrados nos episódios de uma série
+type SeriesIntros struct {
+	Series     string                   `json:"series"`
+	Season     int                      `json:"season,omitempty"`
+	AnalyzedAt time.Time                `json:"analyzedAt"`
+	Episodes   map[string][]SkipSegment `json:"episodes"` // chave: MediaKey do episódio
+}
+
+// IntroCache guarda o resultado das análises (intros.json), para não refazer a cada reprodução
+type IntroCache struct {
+	mu     sync.Mutex
+	path   string
+	series map[string]*SeriesIntros
+}
+
+// DefaultIntroCachePath retorna o caminho do intros.json na pasta de configuração
+func DefaultIntroCachePath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "intros.json"), nil
+}
+
+// OpenIntroCache lê o cache (vazio se o arquivo não existir)
+func OpenIntroCache(path string) (*IntroCache, error) {
+	c := &IntroCache{path: path, series: make(map[string]*SeriesIntros)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.series); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// OpenDefaultIntroCache abre o intros.json da pasta de configuração
+func OpenDefaultIntroCache() (*IntroCache, error) {
+	path, err := DefaultIntroCachePath()
+	if err != nil {
+		return nil, err
+	}
+	return OpenIntroCache(path)
+}
+
+// seriesCacheKey agrupa os episódios por série e temporada
+func seriesCacheKey(info ReleaseInfo) string {
+	season := info.Season
+	if season == 0 {
+		season = 1
+	}
+	return fmt.Sprintf("%s:s%02d", strings.ToLower(info.Series), season)
+}
+
+// Segments retorna os trechos de um episódio (chave de MediaKey)
+func (c *IntroCache) Segments(key string) ([]SkipSegment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range c.series {
+		if segments, ok := s.Episodes[key]; ok {
+			return append([]SkipSegment(nil), segments...), true
+		}
+	}
+	return nil, false
+}
+
+// Missing retorna os arquivos que ainda não foram analisados
+func (c *IntroCache) Missing(paths []string) []string {
+	var missing []string
+	for _, path := range paths {
+		if _, ok := c.Segments(MediaKey(path)); !ok {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
+
+// Store guarda o resultado de uma análise (episódios com erro ficam de fora)
+func (c *IntroCache) Store(a *IntroAnalysis) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, ep := range a.Episodes {
+		if ep.Error != "" {
+			continue
+		}
+		info := ParseReleasePath(ep.Path)
+		key := seriesCacheKey(info)
+		s, ok := c.series[key]
+		if !ok {
+			s = &SeriesIntros{Series: info.Series, Season: info.Season, Episodes: make(map[string][]SkipSegment)}
+			c.series[key] = s
+		}
+		s.AnalyzedAt = now
+		s.Episodes[ep.Key] = ep.Segments
+	}
+}
+
+// Save grava o intros.json
+func (c *IntroCache) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.series, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(c.path, data)
+}
+
+// --- Integração com o Player ---
+
+// SetIntroCache liga os trechos detectados por áudio (usados quando o arquivo não tem capítulos)
+func (p *Player) SetIntroCache(c *IntroCache) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.intros = c
+}
+
+// cachedSegments retorna os trechos detectados por áudio para um arquivo
+func (p *Player) cachedSegments(path string) []SkipSegment {
+	p.mu.Lock()
+	c := p.intros
+	p.mu.Unlock()
+
+	if c == nil || path == "" {
+		return nil
+	}
+	segments, _ := c.Segments(MediaKey(path))
+	return segments
+}
+
+// AnalyzeIntrosAsync analisa os episódios em segundo plano e guarda no cache
+// Ao terminar publica IntroAnalysisEvent e, se o arquivo atual foi analisado, passa a usar os trechos
+func (p *Player) AnalyzeIntrosAsync(paths []string) error {
+	p.mu.Lock()
+	c := p.intros
+	p.mu.Unlock()
+
+	if c == nil {
+		return fmt.Errorf("cache de aberturas desligado (veja SetIntroCache)")
+	}
+	if len(paths) < 2 {
+		return fmt.Errorf("são necessários pelo menos 2 episódios da mesma série")
+	}
+
+	go func() {
+		fmt.Printf("🎵 Analisando aberturas/encerramentos de %d episódios...\n", len(paths))
+		result, err := AnalyzeIntros(context.Background(), paths, DefaultIntroOptions())
+		if err != nil {
+			fmt.Printf("⚠️ Análise de aberturas falhou: %v\n", err)
+			p.emit(IntroAnalysisEvent{Episodes: len(paths), Error: err.Error()})
+			return
+		}
+
+		c.Store(result)
+		if err := c.Save(); err != nil {
+			fmt.Printf("⚠️ Não foi possível salvar o cache de aberturas: %v\n", err)
+		}
+		fmt.Printf("✓ Aberturas/encerramentos encontrados em %d de %d episódios\n", result.Found(), len(paths))
+		p.emit(IntroAnalysisEvent{Series: result.Series, Episodes: len(paths), Found: result.Found()})
+		p.refreshCachedSegments()
+	}()
+	return nil
+}
+
+// refreshCachedSegments aplica o cache ao arquivo atual se ele não tiver capítulos de OP/ED
+func (p *Player) refreshCachedSegments() {
+	p.mu.Lock()
+	path, hasChapters := p.skip.path, false
+	for _, seg := range p.skip.detected {
+		hasChapters = hasChapters || seg.Source == "chapters"
+	}
+	p.mu.Unlock()
+
+	if hasChapters {
+		return
+	}
+	segments := p.cachedSegments(path)
+	if len(segments) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	if p.skip.path != path {
+		p.mu.Unlock()
+		return
+	}
+	p.skip.detected = segments
+	ev := p.rebuildSegments()
+	p.mu.Unlock()
+
+	p.emit(ev)
+}
@@ -0,0 +1,239 @@
+package player
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestWAV grava amostras mono em um WAV PCM 16 bits com channels canais iguais
+func writeTestWAV(t *testing.T, path string, samples []float32, rate, channels int) {
+	t.Helper()
+
+	data := make([]byte, 44+len(samples)*2*channels)
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], uint16(channels))
+	binary.LittleEndian.PutUint32(data[24:], uint32(rate))
+	binary.LittleEndian.PutUint32(data[28:], uint32(rate*2*channels))
+	binary.LittleEndian.PutUint16(data[32:], uint16(2*channels))
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(len(samples)*2*channels))
+	for i, s := range samples {
+		v := int16(math.Max(-1, math.Min(1, float64(s))) * 32767)
+		for c := 0; c < channels; c++ {
+			binary.LittleEndian.PutUint16(data[44+(i*channels+c)*2:], uint16(v))
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// noise gera ruído diferente para cada seed (falas, trilha do episódio)
+func noise(seed int64, seconds float64) []float32 {
+	r := rand.New(rand.NewSource(seed))
+	out := make([]float32, int(seconds*fingerprintRate))
+	for i := range out {
+		out[i] = float32(r.NormFloat64() * 0.2)
+	}
+	return out
+}
+
+// song gera uma sequência de acordes que se repete igual em todos os episódios (OP/ED)
+func song(root float64, seconds float64) []float32 {
+	progression := [][]float64{{0, 4, 7}, {5, 9, 12}, {7, 11, 14}, {-3, 0, 4}, {2, 5, 9}}
+	out := make([]float32, int(seconds*fingerprintRate))
+	for i := range out {
+		tm := float64(i) / fingerprintRate
+		chord := progression[int(tm/0.8)%len(progression)]
+		v := 0.0
+		for _, semitone := range chord {
+			v += math.Sin(2 * math.Pi * root * math.Pow(2, semitone/12) * tm)
+		}
+		out[i] = float32(v * 0.2)
+	}
+	return out
+}
+
+// wavExtractor é um AudioExtractor que recorta WAVs gravados pelo teste
+func wavExtractor(ctx context.Context, path string, start, length float64) (*AudioClip, error) {
+	samples, rate, err := ReadWAV(path)
+	if err != nil {
+		return nil, err
+	}
+	duration := float64(len(samples)) / float64(rate)
+	if start < 0 {
+		start = math.Max(0, duration+start)
+	}
+	from := int(start * float64(rate))
+	to := min(len(samples), from+int(length*float64(rate)))
+	return &AudioClip{Samples: samples[from:to], Rate: rate, Start: start, Duration: duration}, nil
+}
+
+func TestReadWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stereo.wav")
+	want := []float32{0, 0.5, -0.5, 0.25}
+	writeTestWAV(t, path, want, 22050, 2)
+
+	got, rate, err := ReadWAV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 22050 || len(got) != len(want) {
+		t.Fatalf("ReadWAV = %d amostras a %dHz, quer %d a 22050Hz", len(got), rate, len(want))
+	}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-3 {
+			t.Errorf("amostra %d = %v, quer %v", i, got[i], want[i])
+		}
+	}
+
+	if _, _, err := ReadWAV(filepath.Join(t.TempDir(), "nada.wav")); err == nil {
+		t.Error("ReadWAV de arquivo inexistente deveria falhar")
+	}
+}
+
+func TestFindCommonSegment(t *testing.T) {
+	minFrames := int(math.Round(20 / FingerprintStep))
+	op := song(220, 40)
+	a := append(append(noise(1, 12.3), op...), noise(2, 20)...)
+	b := append(append(noise(3, 31.7), op...), noise(4, 5)...)
+
+	m, ok := findCommonSegment(ChromaFingerprint(a, fingerprintRate), ChromaFingerprint(b, fingerprintRate), minFrames)
+	if !ok {
+		t.Fatal("trecho em comum não encontrado")
+	}
+	startA := float64(m.StartA) * FingerprintStep
+	startB := float64(m.StartB) * FingerprintStep
+	length := float64(m.Length) * FingerprintStep
+	if math.Abs(startA-12.3) > 1 || math.Abs(startB-31.7) > 1 || math.Abs(length-40) > 1.5 {
+		t.Errorf("trecho = A %.1fs, B %.1fs, %.1fs; quer A 12.3s, B 31.7s, 40s", startA, startB, length)
+	}
+
+	// Episódios sem nada em comum
+	if m, ok := findCommonSegment(ChromaFingerprint(noise(5, 60), fingerprintRate), ChromaFingerprint(noise(6, 60), fingerprintRate), minFrames); ok {
+		t.Errorf("ruídos diferentes casaram: %+v", m)
+	}
+}
+
+func TestAnalyzeIntros(t *testing.T) {
+	dir := t.TempDir()
+	op, ed := song(220, 45), song(330, 35)
+
+	// Cold open de tamanho diferente em cada episódio; o 3 não tem o ED de sempre
+	episodes := []struct {
+		coldOpen float64
+		hasED    bool
+	}{{15, true}, {32.5, true}, {8, false}}
+
+	var paths []string
+	for i, ep := range episodes {
+		seed := int64(i * 10)
+		audio := append(noise(seed, ep.coldOpen), op...)
+		audio = append(audio, noise(seed+1, 120-ep.coldOpen)...)
+		if ep.hasED {
+			audio = append(audio, ed...)
+		} else {
+			audio = append(audio, noise(seed+2, 35)...)
+		}
+		audio = append(audio, noise(seed+3, 10)...) // prévia do próximo episódio
+
+		// WAV com nome de episódio: o wavExtractor lê o áudio, MediaKey/ParseRelease leem o nome
+		path := filepath.Join(dir, fmt.Sprintf("[Grupo] Teste - %02d.mkv", i+1))
+		writeTestWAV(t, path, audio, fingerprintRate, 1)
+		paths = append(paths, path)
+	}
+
+	opts := DefaultIntroOptions()
+	opts.Window = 90
+	opts.Extract = wavExtractor
+	result, err := AnalyzeIntros(context.Background(), paths, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Series != "Teste" || len(result.Episodes) != 3 {
+		t.Fatalf("análise = %q com %d episódios", result.Series, len(result.Episodes))
+	}
+
+	for i, ep := range episodes {
+		got := result.Episodes[i]
+		wantOP := SkipSegment{Kind: SegmentOpening, Start: ep.coldOpen, End: ep.coldOpen + 45}
+		wantED := SkipSegment{Kind: SegmentEnding, Start: 165, End: 200}
+
+		var haveOP, haveED *SkipSegment
+		for j := range got.Segments {
+			switch got.Segments[j].Kind {
+			case SegmentOpening:
+				haveOP = &got.Segments[j]
+			case SegmentEnding:
+				haveED = &got.Segments[j]
+			}
+		}
+
+		if haveOP == nil || !closeSegment(*haveOP, wantOP) {
+			t.Errorf("episódio %d: OP = %+v, quer ~%+v", i+1, haveOP, wantOP)
+		}
+		switch {
+		case ep.hasED && (haveED == nil || !closeSegment(*haveED, wantED)):
+			t.Errorf("episódio %d: ED = %+v, quer ~%+v", i+1, haveED, wantED)
+		case !ep.hasED && haveED != nil:
+			t.Errorf("episódio %d: ED inesperado %+v", i+1, *haveED)
+		}
+	}
+
+	// Episódio que não abre vira erro no resultado, sem parar a análise
+	broken := append([]string{filepath.Join(dir, "[Grupo] Teste - 00.mkv")}, paths[:2]...)
+	result, err = AnalyzeIntros(context.Background(), broken, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Episodes[0].Error == "" || result.Found() != 2 {
+		t.Errorf("com episódio quebrado: erro %q, %d encontrados", result.Episodes[0].Error, result.Found())
+	}
+}
+
+// closeSegment compara trechos com a tolerância de alguns frames
+func closeSegment(got, want SkipSegment) bool {
+	return got.Kind == want.Kind && math.Abs(got.Start-want.Start) <= 1 && math.Abs(got.End-want.End) <= 1.5
+}
+
+func TestIntroCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intros.json")
+	cache, err := OpenIntroCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ep1 := "/anime/[SubsPlease] Frieren - 01 (1080p).mkv"
+	ep2 := "/anime/[SubsPlease] Frieren - 02 (1080p).mkv"
+	segments := []SkipSegment{{Kind: SegmentOpening, Start: 60, End: 150, Source: "fingerprint"}}
+	cache.Store(&IntroAnalysis{Series: "Frieren", Episodes: []EpisodeIntro{
+		{Path: ep1, Key: MediaKey(ep1), Segments: segments},
+		{Path: ep2, Key: MediaKey(ep2), Error: "falhou"},
+	}})
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenIntroCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := reopened.Segments(MediaKey("/outra/pasta/[Erai-raws] Frieren - 01 [720p].mkv"))
+	if !ok || len(got) != 1 || got[0] != segments[0] {
+		t.Errorf("Segments = %+v, %v; quer %+v", got, ok, segments)
+	}
+	if missing := reopened.Missing([]string{ep1, ep2}); len(missing) != 1 || missing[0] != ep2 {
+		t.Errorf("Missing = %v, quer [%s]", missing, ep2)
+	}
+}
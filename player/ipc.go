@@ -0,0 +1,106 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ipcTimeout limita o tempo de conexão e de resposta do IPC
+const ipcTimeout = 3 * time.Second
+
+// EnableIPC abre o servidor JSON IPC do MPV (input-ipc-server) no caminho informado
+// É por ele que "player4k remote" controla uma instância em execução
+func (p *Player) EnableIPC(path string) error {
+	return p.engine.SetPropertyString("input-ipc-server", path)
+}
+
+// IPCClient fala o protocolo JSON IPC do MPV com uma instância em execução
+type IPCClient struct {
+	conn   io.ReadWriteCloser
+	reader *bufio.Reader
+	nextID int64
+}
+
+// ipcRequest é um comando enviado ao MPV
+type ipcRequest struct {
+	Command   []interface{} `json:"command"`
+	RequestID int64         `json:"request_id"`
+}
+
+// ipcResponse é a resposta (ou evento) recebida do MPV
+type ipcResponse struct {
+	Event     string          `json:"event,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	RequestID int64           `json:"request_id"`
+}
+
+// DialIPC conecta ao socket (ou named pipe no Windows) de uma instância
+func DialIPC(path string) (*IPCClient, error) {
+	conn, err := dialIPC(path, ipcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &IPCClient{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// Command envia um comando do MPV (ex: "set_property", "pause", true) e devolve "data"
+func (c *IPCClient) Command(args ...interface{}) (json.RawMessage, error) {
+	c.nextID++
+	req := ipcRequest{Command: args, RequestID: c.nextID}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	setIPCDeadline(c.conn, time.Now().Add(ipcTimeout))
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("erro ao enviar comando: %w", err)
+	}
+
+	// Eventos chegam misturados com as respostas; procura a do nosso request_id
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler resposta: %w", err)
+		}
+
+		var resp ipcResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			continue
+		}
+		if resp.Event != "" || resp.RequestID != req.RequestID {
+			continue
+		}
+		if resp.Error != "success" {
+			return nil, fmt.Errorf("mpv: %s", resp.Error)
+		}
+		return resp.Data, nil
+	}
+}
+
+// GetProperty lê uma propriedade da instância remota
+func (c *IPCClient) GetProperty(name string) (json.RawMessage, error) {
+	return c.Command("get_property", name)
+}
+
+// SetProperty altera uma propriedade da instância remota
+func (c *IPCClient) SetProperty(name string, value interface{}) error {
+	_, err := c.Command("set_property", name, value)
+	return err
+}
+
+// Close encerra a conexão
+func (c *IPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// setIPCDeadline aplica um prazo se a conexão suportar (net.Conn, pipes)
+func setIPCDeadline(conn io.ReadWriteCloser, t time.Time) {
+	if d, ok := conn.(interface{ SetDeadline(time.Time) error }); ok {
+		d.SetDeadline(t)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package player
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// DefaultIPCPath retorna o socket padrão ($XDG_RUNTIME_DIR ou pasta temporária)
+func DefaultIPCPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "player4k.sock")
+}
+
+// dialIPC conecta ao socket Unix do MPV
+func dialIPC(path string, timeout time.Duration) (io.ReadWriteCloser, error) {
+	return net.DialTimeout("unix", path, timeout)
+}
+
+// DefaultRPCPath retorna o socket padrão do servidor JSON-RPC
+func DefaultRPCPath() string {
+	return filepath.Join(filepath.Dir(DefaultIPCPath()), "player4k-rpc.sock")
+}
+
+// listenUnix abre o socket já com permissão 0600
+// Com a umask do processo o socket nasceria aberto a outros usuários até o Chmod
+func listenUnix(path string) (net.Listener, error) {
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+
+	return net.Listen("unix", path)
+}
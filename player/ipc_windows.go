@@ -0,0 +1,30 @@
+package player
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultIPCPath retorna o named pipe padrão
+func DefaultIPCPath() string {
+	return `\\.\pipe\player4k`
+}
+
+// dialIPC abre o named pipe do MPV como um arquivo comum
+func dialIPC(path string, _ time.Duration) (io.ReadWriteCloser, error) {
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
+
+// DefaultRPCPath retorna o socket padrão do servidor JSON-RPC
+// O Windows 10+ suporta AF_UNIX; o arquivo fica na pasta temporária do usuário
+func DefaultRPCPath() string {
+	return filepath.Join(os.TempDir(), "player4k-rpc.sock")
+}
+
+// listenUnix abre o socket AF_UNIX (no Windows não há umask; a pasta temporária já é do usuário)
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,492 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TrackPreferences são as regras para escolher áudio e legenda ao abrir um arquivo
+// Ex: áudio jpn; legendas pt-BR > en, completas em vez de Signs & Songs
+type TrackPreferences struct {
+	// Audio lista idiomas em ordem de preferência ("jpn", "pt-BR", "en")
+	Audio []string `json:"audio,omitempty"`
+	// Subtitles lista idiomas em ordem; "no" desliga a legenda se a busca chegar nele
+	Subtitles []string `json:"subtitles,omitempty"`
+	// AllowSignsOnly aceita Signs & Songs como legenda principal (por padrão só se não houver completa)
+	AllowSignsOnly bool `json:"allowSignsOnly,omitempty"`
+	// AllowForcedOnly aceita legendas só com falas forçadas (por padrão são evitadas)
+	AllowForcedOnly bool `json:"allowForcedOnly,omitempty"`
+	// FullSubsWithDub mantém a legenda completa quando o áudio já está no idioma dela
+	// Por padrão, com dublagem só entram letreiros (Signs & Songs/forçadas) ou nenhuma legenda
+	FullSubsWithDub bool `json:"fullSubsWithDub,omitempty"`
+}
+
+// SeriesTrackPreferences troca as regras de uma série
+// Listas vazias e opções ausentes (nil) herdam as globais; false explícito desliga
+type SeriesTrackPreferences struct {
+	Audio           []string `json:"audio,omitempty"`
+	Subtitles       []string `json:"subtitles,omitempty"`
+	AllowSignsOnly  *bool    `json:"allowSignsOnly,omitempty"`
+	AllowForcedOnly *bool    `json:"allowForcedOnly,omitempty"`
+	FullSubsWithDub *bool    `json:"fullSubsWithDub,omitempty"`
+}
+
+// LanguagePreferences são as regras globais mais os overrides por série
+type LanguagePreferences struct {
+	TrackPreferences
+	// Series troca as regras de uma série (chave: nome da série, sem diferenciar maiúsculas)
+	Series map[string]SeriesTrackPreferences `json:"series,omitempty"`
+}
+
+// ForSeries retorna as regras de uma série
+// Sem série conhecida, procura uma chave contida no título (ex: "Frieren" em "Frieren - 05")
+func (l LanguagePreferences) ForSeries(series, title string) TrackPreferences {
+	prefs := l.TrackPreferences
+	key, ok := l.seriesKey(series, title)
+	if !ok {
+		return prefs
+	}
+
+	o := l.Series[key]
+	if len(o.Audio) > 0 {
+		prefs.Audio = o.Audio
+	}
+	if len(o.Subtitles) > 0 {
+		prefs.Subtitles = o.Subtitles
+	}
+	if o.AllowSignsOnly != nil {
+		prefs.AllowSignsOnly = *o.AllowSignsOnly
+	}
+	if o.AllowForcedOnly != nil {
+		prefs.AllowForcedOnly = *o.AllowForcedOnly
+	}
+	if o.FullSubsWithDub != nil {
+		prefs.FullSubsWithDub = *o.FullSubsWithDub
+	}
+	return prefs
+}
+
+// seriesKey encontra o override da série (a chave mais longa contida no título vence)
+// No título a chave precisa estar entre separadores: "Ao" não casa com "Naoko"
+func (l LanguagePreferences) seriesKey(series, title string) (string, bool) {
+	series = strings.ToLower(strings.TrimSpace(series))
+	title = strings.ToLower(title)
+
+	best := ""
+	found := false
+	for key := range l.Series {
+		k := strings.ToLower(strings.TrimSpace(key))
+		if k == "" {
+			continue
+		}
+		if series != "" {
+			if k == series {
+				return key, true
+			}
+			continue
+		}
+		if containsWord(title, k) && len(k) > len(best) {
+			best, found = key, true
+		}
+	}
+	return best, found
+}
+
+// containsWord indica se word aparece em s sem letras ou dígitos colados nas pontas
+func containsWord(s, word string) bool {
+	for i := 0; i+len(word) <= len(s); {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		i = start + size
+	}
+	return false
+}
+
+// isWordRune indica se o caractere faz parte de uma palavra
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// TrackSelection é o resultado das regras para uma lista de faixas
+type TrackSelection struct {
+	Audio       *Track   `json:"audio,omitempty"`    // nil mantém a escolha do MPV
+	Subtitle    *Track   `json:"subtitle,omitempty"` // nil mantém a escolha do MPV (ou desliga, veja SubtitleOff)
+	SubtitleOff bool     `json:"subtitleOff"`
+	Reasons     []string `json:"reasons"`
+}
+
+// SelectTracks aplica as regras a uma lista de faixas (track-list)
+// É puro: não fala com o MPV, então pode ser testado com listas de exemplo
+func SelectTracks(tracks []Track, prefs TrackPreferences) TrackSelection {
+	var sel TrackSelection
+
+	audio, reason := selectAudio(tracks, prefs.Audio)
+	sel.Audio = audio
+	if reason != "" {
+		sel.Reasons = append(sel.Reasons, reason)
+	}
+
+	// O idioma do áudio que vai tocar decide se a legenda é de dublagem
+	playing := audio
+	if playing == nil {
+		playing = currentAudio(tracks)
+	}
+
+	sel.Subtitle, sel.SubtitleOff, reason = selectSubtitle(tracks, prefs, playing)
+	if reason != "" {
+		sel.Reasons = append(sel.Reasons, reason)
+	}
+	return sel
+}
+
+// selectAudio escolhe a primeira faixa de áudio que atende a ordem de idiomas
+// Faixas de comentário só entram se forem as únicas do idioma
+func selectAudio(tracks []Track, prefs []string) (*Track, string) {
+	for _, pref := range prefs {
+		want := parseLang(pref)
+		var best *Track
+		bestScore := 0
+		for i := range tracks {
+			t := &tracks[i]
+			if t.Type != TrackAudio {
+				continue
+			}
+			m := langMatch(want, trackLang(*t))
+			if m == 0 {
+				continue
+			}
+			score := m * 4
+			if t.Default {
+				score += 2
+			}
+			if !isCommentary(*t) {
+				score += 10
+			}
+			if best == nil || score > bestScore {
+				best, bestScore = t, score
+			}
+		}
+		if best != nil {
+			return best, fmt.Sprintf("áudio %s: %s", pref, best.Label)
+		}
+	}
+	if len(prefs) > 0 {
+		return nil, fmt.Sprintf("nenhum áudio em %s, mantendo o do arquivo", strings.Join(prefs, ", "))
+	}
+	return nil, ""
+}
+
+// currentAudio retorna a faixa de áudio selecionada pelo MPV (ou a primeira)
+func currentAudio(tracks []Track) *Track {
+	var first *Track
+	for i := range tracks {
+		t := &tracks[i]
+		if t.Type != TrackAudio {
+			continue
+		}
+		if t.Selected {
+			return t
+		}
+		if first == nil {
+			first = t
+		}
+	}
+	return first
+}
+
+// subKind classifica uma legenda pelo conteúdo
+type subKind int
+
+const (
+	subFull   subKind = iota // diálogos completos
+	subSigns                 // só letreiros e músicas (Signs & Songs)
+	subForced                // só falas forçadas
+)
+
+// classifySub usa a flag forced e o título da faixa
+func classifySub(t Track) subKind {
+	title := strings.ToLower(t.Title)
+	switch {
+	case containsAny(title, "sign", "song", "s&s", "letreiro", "cartaz"):
+		return subSigns
+	case t.Forced || containsAny(title, "forced", "forçad", "forzad"):
+		return subForced
+	}
+	return subFull
+}
+
+// selectSubtitle percorre os idiomas de legenda em duas passadas:
+// primeiro só legendas completas; depois aceita Signs & Songs
+func selectSubtitle(tracks []Track, prefs TrackPreferences, audio *Track) (*Track, bool, string) {
+	for pass := 0; pass < 2; pass++ {
+		for _, pref := range prefs.Subtitles {
+			if isOffPref(pref) {
+				return nil, true, "legenda desligada pelas preferências"
+			}
+
+			want := parseLang(pref)
+			dub := audio != nil && !prefs.FullSubsWithDub && langMatch(want, trackLang(*audio)) > 0
+
+			var best *Track
+			bestScore := 0
+			for i := range tracks {
+				t := &tracks[i]
+				if t.Type != TrackSubtitle {
+					continue
+				}
+				m := langMatch(want, trackLang(*t))
+				kind := classifySub(*t)
+				if m == 0 || !subAcceptable(kind, pass, dub, prefs) {
+					continue
+				}
+				score := m * 2
+				if t.Default {
+					score++
+				}
+				// Com dublagem, Signs & Songs traz também as músicas
+				if dub && kind == subSigns {
+					score += 4
+				}
+				if best == nil || score > bestScore {
+					best, bestScore = t, score
+				}
+			}
+
+			if best != nil {
+				if dub {
+					return best, false, fmt.Sprintf("áudio já em %s, usando só letreiros: %s", pref, best.Label)
+				}
+				return best, false, fmt.Sprintf("legenda %s: %s", pref, best.Label)
+			}
+			// Dublado no idioma da legenda e sem letreiros: nenhuma legenda
+			if dub {
+				return nil, true, fmt.Sprintf("áudio já em %s, legenda desligada", pref)
+			}
+		}
+	}
+	if len(prefs.Subtitles) > 0 {
+		return nil, false, fmt.Sprintf("nenhuma legenda em %s, mantendo a do arquivo", strings.Join(prefs.Subtitles, ", "))
+	}
+	return nil, false, ""
+}
+
+// subAcceptable diz se um tipo de legenda pode ser escolhido nesta passada
+func subAcceptable(kind subKind, pass int, dub bool, prefs TrackPreferences) bool {
+	if dub {
+		return kind != subFull
+	}
+	switch kind {
+	case subFull:
+		return true
+	case subSigns:
+		return prefs.AllowSignsOnly || pass == 1
+	case subForced:
+		return prefs.AllowForcedOnly
+	}
+	return false
+}
+
+// isOffPref indica uma entrada que desliga a legenda
+func isOffPref(pref string) bool {
+	switch strings.ToLower(strings.TrimSpace(pref)) {
+	case "no", "none", "off":
+		return true
+	}
+	return false
+}
+
+// isCommentary detecta faixas de comentário pelo título
+func isCommentary(t Track) bool {
+	return containsAny(strings.ToLower(t.Title), "comment", "comentário", "comentario")
+}
+
+// containsAny indica se s contém algum dos trechos
+func containsAny(s string, parts ...string) bool {
+	for _, part := range parts {
+		if strings.Contains(s, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Idiomas ---
+
+// langTag é um idioma normalizado: base ISO 639-1 e região opcional ("pt" + "br")
+type langTag struct {
+	base   string
+	region string
+}
+
+// langAliases converte códigos ISO 639-2 (e apelidos comuns) para ISO 639-1
+var langAliases = map[string]langTag{
+	"jpn": {"ja", ""}, "jp": {"ja", ""},
+	"eng": {"en", ""},
+	"por": {"pt", ""}, "pob": {"pt", "br"}, "ptb": {"pt", "br"},
+	"spa": {"es", ""}, "esl": {"es", "419"}, "lat": {"es", "419"},
+	"fre": {"fr", ""}, "fra": {"fr", ""},
+	"ger": {"de", ""}, "deu": {"de", ""},
+	"ita": {"it", ""},
+	"rus": {"ru", ""},
+	"chi": {"zh", ""}, "zho": {"zh", ""},
+	"kor": {"ko", ""},
+	"ara": {"ar", ""},
+	"hin": {"hi", ""},
+	"pol": {"pl", ""},
+	"tur": {"tr", ""},
+	"ind": {"id", ""},
+	"tha": {"th", ""},
+	"vie": {"vi", ""},
+}
+
+// parseLang normaliza "pt-BR", "pt_br", "por", "pob", "es-419"...
+func parseLang(s string) langTag {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "_", "-")
+
+	base, region, _ := strings.Cut(s, "-")
+	if alias, ok := langAliases[base]; ok {
+		if region == "" {
+			region = alias.region
+		}
+		base = alias.base
+	}
+	// Regiões latino-americanas do espanhol viram "419"
+	if base == "es" && (region == "la" || region == "mx" || region == "lat") {
+		region = "419"
+	}
+	return langTag{base: base, region: region}
+}
+
+// trackLang retorna o idioma da faixa, completando a região pelo título
+// Ex: lang "por" com título "Português (Brasil)" vira pt-BR
+func trackLang(t Track) langTag {
+	tag := parseLang(t.Lang)
+	if tag.region != "" {
+		return tag
+	}
+
+	title := strings.ToLower(t.Title)
+	switch tag.base {
+	case "pt":
+		switch {
+		case containsAny(title, "brasil", "brazil", "pt-br", "[br]", "(br)"):
+			tag.region = "br"
+		case containsAny(title, "portugal", "europeu", "european", "pt-pt"):
+			tag.region = "pt"
+		}
+	case "es":
+		switch {
+		case containsAny(title, "latino", "latin", "latam", "419", "méxico", "mexico"):
+			tag.region = "419"
+		case containsAny(title, "españa", "espana", "spain", "castellano", "castilian", "europe"):
+			tag.region = "es"
+		}
+	}
+	return tag
+}
+
+// langMatch compara a preferência com o idioma da faixa
+// 0 = não serve, 1 = idioma certo com região desconhecida, 2 = exato
+// Uma preferência sem região ("pt") aceita qualquer região
+func langMatch(want, have langTag) int {
+	if want.base == "" || want.base != have.base {
+		return 0
+	}
+	switch {
+	case want.region == have.region:
+		return 2
+	case want.region == "":
+		return 2
+	case have.region == "":
+		return 1
+	}
+	return 0
+}
+
+// ParseLanguageList converte "pt-BR,en" (ou "pt-BR > en") na lista usada por TrackPreferences
+func ParseLanguageList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '>' || r == ' ' })
+}
+
+// --- Integração com o Player ---
+
+// SetLanguagePreferences define as regras de áudio/legenda aplicadas a cada arquivo aberto
+func (p *Player) SetLanguagePreferences(prefs LanguagePreferences) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.langPrefs = &prefs
+}
+
+// LanguagePreferences retorna as regras atuais (vazias se nenhuma foi definida)
+func (p *Player) LanguagePreferences() LanguagePreferences {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.langPrefs == nil {
+		return LanguagePreferences{}
+	}
+	return *p.langPrefs
+}
+
+// SetSeries informa a série do próximo arquivo (override de idioma e perfil da série)
+// Vazio faz o player procurar a série pelo título do arquivo
+func (p *Player) SetSeries(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.series = name
+}
+
+// applyTrackPreferences escolhe áudio e legenda quando um arquivo termina de abrir
+// audioLang (do perfil da série) passa na frente dos idiomas de áudio das preferências
+func (p *Player) applyTrackPreferences(title, audioLang string) {
+	p.mu.Lock()
+	langPrefs, series := p.langPrefs, p.series
+	p.mu.Unlock()
+
+	var prefs TrackPreferences
+	if langPrefs != nil {
+		prefs = langPrefs.ForSeries(series, title)
+	}
+	if audioLang != "" {
+		prefs.Audio = append([]string{audioLang}, prefs.Audio...)
+	}
+	if len(prefs.Audio) == 0 && len(prefs.Subtitles) == 0 {
+		return
+	}
+
+	// O observer de track-list pode ainda ter a lista do arquivo anterior
+	tracks, err := parseTrackList(p.getString("track-list"))
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		return
+	}
+
+	sel := SelectTracks(tracks, prefs)
+	if sel.Audio != nil && !sel.Audio.Selected {
+		p.SetAudioTrack(int(sel.Audio.ID))
+	}
+	switch {
+	case sel.Subtitle != nil && !sel.Subtitle.Selected:
+		p.SetSubtitleTrack(int(sel.Subtitle.ID))
+	case sel.SubtitleOff:
+		p.SetSubtitleTrack(0)
+	}
+	for _, reason := range sel.Reasons {
+		fmt.Printf("🌐 %s\n", reason)
+	}
+}
@@ -0,0 +1,233 @@
+package player
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fansubTracks é um release típico: jpn + dublagens, legendas completas, S&S e forçadas
+var fansubTracks = []Track{
+	{ID: 1, Type: TrackVideo, Codec: "hevc"},
+	{ID: 1, Type: TrackAudio, Lang: "jpn", Title: "Japanese", Default: true, Selected: true},
+	{ID: 2, Type: TrackAudio, Lang: "por", Title: "Português (Brasil)"},
+	{ID: 3, Type: TrackAudio, Lang: "eng", Title: "English"},
+	{ID: 4, Type: TrackAudio, Lang: "jpn", Title: "Commentary"},
+	{ID: 1, Type: TrackSubtitle, Lang: "eng", Title: "Full Subtitles", Default: true, Selected: true},
+	{ID: 2, Type: TrackSubtitle, Lang: "eng", Title: "Signs & Songs"},
+	{ID: 3, Type: TrackSubtitle, Lang: "por", Title: "Português (Brasil)"},
+	{ID: 4, Type: TrackSubtitle, Lang: "pob", Title: "Forced", Forced: true},
+	{ID: 5, Type: TrackSubtitle, Lang: "spa", Title: "Español (Latino)"},
+}
+
+// simulcastTracks tem só áudio japonês (ou dublado) e legendas completas
+var simulcastTracks = []Track{
+	{ID: 1, Type: TrackVideo, Codec: "h264"},
+	{ID: 1, Type: TrackAudio, Lang: "jpn", Selected: true},
+	{ID: 2, Type: TrackAudio, Lang: "por", Title: "Português (Brasil)"},
+	{ID: 1, Type: TrackSubtitle, Lang: "por", Title: "Português (Brasil)", Selected: true},
+	{ID: 2, Type: TrackSubtitle, Lang: "eng", Title: "English"},
+}
+
+// signsOnlyTracks não tem legenda completa em inglês, nem completa em pt-BR
+var signsOnlyTracks = []Track{
+	{ID: 1, Type: TrackAudio, Lang: "jpn", Selected: true},
+	{ID: 1, Type: TrackSubtitle, Lang: "eng", Title: "Signs & Songs"},
+	{ID: 2, Type: TrackSubtitle, Lang: "pob", Title: "Forçadas", Forced: true},
+}
+
+func TestSelectTracks(t *testing.T) {
+	tests := []struct {
+		name     string
+		tracks   []Track
+		prefs    TrackPreferences
+		audio    int64 // 0 = mantém o do arquivo
+		subtitle int64 // 0 = mantém o do arquivo (ou desliga, se off)
+		off      bool
+	}{
+		{
+			"jpn com legenda completa em inglês, não S&S",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"jpn"}, Subtitles: []string{"en"}},
+			1, 1, false,
+		},
+		{
+			"jpn com pt-BR antes de en",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"ja"}, Subtitles: []string{"pt-BR", "en"}},
+			1, 3, false,
+		},
+		{
+			"dublado em inglês fica só com Signs & Songs",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"en"}, Subtitles: []string{"en"}},
+			3, 2, false,
+		},
+		{
+			"dublado em pt-BR fica com a legenda forçada",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"pt-BR"}, Subtitles: []string{"pt-BR", "en"}},
+			2, 4, false,
+		},
+		{
+			"dublado em pt-BR sem letreiros desliga a legenda",
+			simulcastTracks,
+			TrackPreferences{Audio: []string{"pt-BR"}, Subtitles: []string{"pt-BR", "en"}},
+			2, 0, true,
+		},
+		{
+			"fullSubsWithDub mantém a legenda completa com dublagem",
+			simulcastTracks,
+			TrackPreferences{Audio: []string{"pt-BR"}, Subtitles: []string{"pt-BR"}, FullSubsWithDub: true},
+			2, 1, false,
+		},
+		{
+			"sem dublagem no arquivo cai para o próximo idioma de áudio",
+			signsOnlyTracks,
+			TrackPreferences{Audio: []string{"pt-BR", "jpn"}, Subtitles: []string{"en"}},
+			1, 1, false,
+		},
+		{
+			"comentário só se for o único áudio do idioma",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"jpn"}},
+			1, 0, false,
+		},
+		{
+			"legenda só forçada é evitada por padrão",
+			signsOnlyTracks,
+			TrackPreferences{Audio: []string{"jpn"}, Subtitles: []string{"pt-BR"}},
+			1, 0, false,
+		},
+		{
+			"allowForcedOnly aceita legenda só forçada",
+			signsOnlyTracks,
+			TrackPreferences{Audio: []string{"jpn"}, Subtitles: []string{"pt-BR"}, AllowForcedOnly: true},
+			1, 2, false,
+		},
+		{
+			"região diferente não serve (es-ES contra latino)",
+			fansubTracks,
+			TrackPreferences{Subtitles: []string{"es-ES"}},
+			0, 0, false,
+		},
+		{
+			"nenhuma faixa nos idiomas pedidos mantém as do arquivo",
+			fansubTracks,
+			TrackPreferences{Audio: []string{"fre"}, Subtitles: []string{"de"}},
+			0, 0, false,
+		},
+		{
+			"\"no\" desliga a legenda quando a busca chega nele",
+			fansubTracks,
+			TrackPreferences{Subtitles: []string{"de", "no"}},
+			0, 0, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := SelectTracks(tt.tracks, tt.prefs)
+
+			var audio, subtitle int64
+			if sel.Audio != nil {
+				audio = sel.Audio.ID
+				if sel.Audio.Type != TrackAudio {
+					t.Errorf("Audio é do tipo %s", sel.Audio.Type)
+				}
+			}
+			if sel.Subtitle != nil {
+				subtitle = sel.Subtitle.ID
+				if sel.Subtitle.Type != TrackSubtitle {
+					t.Errorf("Subtitle é do tipo %s", sel.Subtitle.Type)
+				}
+			}
+			if audio != tt.audio || subtitle != tt.subtitle || sel.SubtitleOff != tt.off {
+				t.Errorf("SelectTracks = áudio %d, legenda %d, off %v; want áudio %d, legenda %d, off %v\nreasons: %q",
+					audio, subtitle, sel.SubtitleOff, tt.audio, tt.subtitle, tt.off, sel.Reasons)
+			}
+			if len(tt.prefs.Audio)+len(tt.prefs.Subtitles) > 0 && len(sel.Reasons) == 0 {
+				t.Error("SelectTracks sem motivo")
+			}
+		})
+	}
+}
+
+func TestForSeries(t *testing.T) {
+	yes, no := true, false
+	prefs := LanguagePreferences{
+		TrackPreferences: TrackPreferences{Audio: []string{"ja"}, Subtitles: []string{"pt-BR", "en"}, FullSubsWithDub: true},
+		Series: map[string]SeriesTrackPreferences{
+			"Frieren":           {Audio: []string{"pt-BR"}},
+			"Sousou no Frieren": {Subtitles: []string{"en"}, AllowSignsOnly: &yes},
+			"Ao":                {Audio: []string{"en"}, FullSubsWithDub: &no},
+		},
+	}
+
+	tests := []struct {
+		series, title string
+		want          TrackPreferences
+	}{
+		// Série informada: chave exata, sem diferenciar maiúsculas; o que falta herda as globais
+		{"frieren", "", TrackPreferences{Audio: []string{"pt-BR"}, Subtitles: []string{"pt-BR", "en"}, FullSubsWithDub: true}},
+		// Pelo título: a chave mais longa contida vence
+		{"", "Sousou no Frieren - 05", TrackPreferences{Audio: []string{"ja"}, Subtitles: []string{"en"}, AllowSignsOnly: true, FullSubsWithDub: true}},
+		{"", "[SubsPlease] Frieren - 12 (1080p)", TrackPreferences{Audio: []string{"pt-BR"}, Subtitles: []string{"pt-BR", "en"}, FullSubsWithDub: true}},
+		// false explícito no override desliga a opção global
+		{"", "[Erai-raws] Ao no Hako - 03", TrackPreferences{Audio: []string{"en"}, Subtitles: []string{"pt-BR", "en"}}},
+		// A chave precisa ser uma palavra do título: "Ao" não casa com "Naoko" nem com "Aoashi"
+		{"", "Naoko - 01", prefs.TrackPreferences},
+		{"", "Aoashi - 01", prefs.TrackPreferences},
+		// Série conhecida sem override não procura pelo título
+		{"Spy x Family", "Frieren", prefs.TrackPreferences},
+		{"", "One Piece - 1085", prefs.TrackPreferences},
+	}
+
+	for _, tt := range tests {
+		got := prefs.ForSeries(tt.series, tt.title)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ForSeries(%q, %q)\n got: %+v\nwant: %+v", tt.series, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestSeriesOverrideJSON(t *testing.T) {
+	var prefs LanguagePreferences
+	data := `{"allowSignsOnly": true, "series": {"Frieren": {"audio": ["pt-BR"]}, "Ao no Hako": {"allowSignsOnly": false}}}`
+	if err := json.Unmarshal([]byte(data), &prefs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem a chave no JSON a opção é herdada; com false, desligada
+	if got := prefs.ForSeries("Frieren", ""); !got.AllowSignsOnly {
+		t.Errorf("Frieren herdou AllowSignsOnly = false, want true")
+	}
+	if got := prefs.ForSeries("Ao no Hako", ""); got.AllowSignsOnly {
+		t.Errorf("Ao no Hako AllowSignsOnly = true, want false")
+	}
+}
+
+func TestApplyTrackPreferencesOverride(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	p.SetLanguagePreferences(LanguagePreferences{
+		TrackPreferences: TrackPreferences{Audio: []string{"ja"}, Subtitles: []string{"pt-BR"}},
+	})
+	e.SetValue("track-list", `[
+		{"id": 1, "type": "audio", "lang": "jpn", "selected": true},
+		{"id": 2, "type": "audio", "lang": "por", "title": "Português (Brasil)"},
+		{"id": 1, "type": "sub", "lang": "por", "title": "Português (Brasil)", "selected": true},
+		{"id": 2, "type": "sub", "lang": "por", "title": "Letreiros"}
+	]`)
+	e.Reset()
+
+	// O idioma salvo para este arquivo (perfil da série) passa na frente das regras globais
+	p.applyTrackPreferences("Frieren - 05", "pt-BR")
+
+	if got, _ := e.Property("aid"); got != "2" {
+		t.Errorf("aid = %q, want 2", got)
+	}
+	if got, _ := e.Property("sid"); got != "2" {
+		t.Errorf("sid = %q, want 2 (letreiros com áudio dublado)", got)
+	}
+}
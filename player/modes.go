@@ -2,10 +2,11 @@ package player
 
 import (
 	"fmt"
-	"path/filepath"
+	"strings"
 )
 
-// PerformanceMode representa os modos de performance
+// PerformanceMode identifica um preset de qualidade
+// Os três modos abaixo são embutidos; outros podem vir da pasta presets/
 type PerformanceMode string
 
 const (
@@ -21,191 +22,105 @@ const (
 
 // ModeInfo contém informações sobre um modo
 type ModeInfo struct {
-	ID          PerformanceMode
-	Name        string
-	Description string
-	Icon        string
-	GPURequired string
+	ID          PerformanceMode `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Icon        string          `json:"icon"`
+	GPURequired string          `json:"gpuRequired"`
+	GPUTier     int             `json:"gpuTier"`
+}
+
+// ParsePerformanceMode converte texto (ex: "high") em um modo conhecido
+func ParsePerformanceMode(s string) (PerformanceMode, bool) {
+	mode := PerformanceMode(strings.ToLower(strings.TrimSpace(s)))
+	_, ok := GetPreset(mode)
+	return mode, ok
 }
 
 // GetModeInfo retorna informações sobre um modo
 func GetModeInfo(mode PerformanceMode) ModeInfo {
-	switch mode {
-	case ModeLow:
-		return ModeInfo{
-			ID:          ModeLow,
-			Name:        "Econômico",
-			Description: "Otimizado para bateria e compatibilidade",
-			Icon:        "🔋",
-			GPURequired: "Qualquer (Intel HD, AMD APU)",
-		}
-	case ModeMedium:
-		return ModeInfo{
-			ID:          ModeMedium,
-			Name:        "Equilibrado",
-			Description: "Qualidade boa com upscaling FSR",
-			Icon:        "⚖️",
-			GPURequired: "GTX 1050 / RX 560 / Intel Iris",
-		}
-	case ModeHigh:
-		return ModeInfo{
-			ID:          ModeHigh,
-			Name:        "Ultra",
-			Description: "Upscaling AI com rede neural profunda",
-			Icon:        "🚀",
-			GPURequired: "RTX 3060 / RX 6700 ou superior",
-		}
+	if pr, ok := GetPreset(mode); ok {
+		return pr.Info()
 	}
 	return ModeInfo{}
 }
 
-// GetAllModes retorna todos os modos disponíveis
+// GetAllModes retorna todos os modos disponíveis (embutidos e da pasta presets)
 func GetAllModes() []ModeInfo {
-	return []ModeInfo{
-		GetModeInfo(ModeLow),
-		GetModeInfo(ModeMedium),
-		GetModeInfo(ModeHigh),
+	all := GetAllPresets()
+	modes := make([]ModeInfo, len(all))
+	for i, pr := range all {
+		modes[i] = pr.Info()
 	}
+	return modes
 }
 
 // SetPerformanceMode aplica um modo de performance
-func (p *Player) SetPerformanceMode(mode PerformanceMode) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	// Limpar shaders anteriores
-	p.mpv.SetPropertyString("glsl-shaders", "")
-
-	info := GetModeInfo(mode)
-	fmt.Printf("%s Ativando modo: %s\n", info.Icon, info.Name)
-
-	switch mode {
-	case ModeLow:
-		p.applyLowMode()
-	case ModeMedium:
-		p.applyMediumMode()
-	case ModeHigh:
-		p.applyHighMode()
+// Se já houver um arquivo carregado, as regras de upscale do preset são reavaliadas
+func (p *Player) SetPerformanceMode(mode PerformanceMode) error {
+	err := p.setPerformanceMode(mode, ModeChange{Mode: mode, Reason: "modo selecionado"})
+	if err != nil {
+		return err
 	}
 
-	p.currentMode = mode
-
-	if p.OnModeChanged != nil {
-		p.OnModeChanged(mode)
+	// Escolha manual vira o teto do ajuste automático
+	p.mu.Lock()
+	if p.adaptive != nil {
+		p.adaptive.SetCeiling(mode)
 	}
-}
-
-// applyLowMode aplica configurações do modo econômico
-func (p *Player) applyLowMode() {
-	// Profile leve
-	p.mpv.SetPropertyString("profile", "fast")
-
-	// Hardware decoding prioritário
-	p.mpv.SetPropertyString("hwdec", "auto-safe")
+	p.mu.Unlock()
 
-	// Escaladores mais leves
-	p.mpv.SetPropertyString("scale", "bilinear")
-	p.mpv.SetPropertyString("cscale", "bilinear")
-	p.mpv.SetPropertyString("dscale", "bilinear")
-
-	// Desativar recursos pesados
-	p.mpv.SetPropertyString("deband", "no")
-	p.mpv.SetPropertyString("interpolation", "no")
-	p.mpv.SetPropertyString("dither-depth", "no")
+	// Trocar o modo desliga o Anime4K (setPerformanceMode limpa os shaders)
+	p.recordProfile(func(sp *SeriesProfile) { sp.Mode, sp.AnimePreset = mode, "" })
+	return nil
+}
 
-	// Renderizador padrão
-	p.mpv.SetPropertyString("vo", "gpu")
+// setPerformanceMode aplica o preset e publica ModeChangedEvent com o motivo informado
+func (p *Player) setPerformanceMode(mode PerformanceMode, change ModeChange) error {
+	preset, ok := GetPreset(mode)
+	if !ok {
+		return fmt.Errorf("modo desconhecido: %s", mode)
+	}
 
-	fmt.Println("  ✓ Decodificação por hardware")
-	fmt.Println("  ✓ Escalamento bilinear (leve)")
-	fmt.Println("  ✓ Debanding desativado")
-}
+	p.mu.Lock()
 
-// applyMediumMode aplica configurações do modo equilibrado
-func (p *Player) applyMediumMode() {
-	// Profile de alta qualidade
-	p.mpv.SetPropertyString("profile", "gpu-hq")
+	// Limpar shaders anteriores
+	p.engine.SetPropertyString("glsl-shaders", "")
 
-	// Hardware decoding
-	p.mpv.SetPropertyString("hwdec", "auto-safe")
+	fmt.Printf("%s Ativando modo: %s\n", preset.Icon, preset.Name)
+	p.applyPreset(preset)
+	p.recordBundleConflicts(overrideMode, p.bundle.Conflicts(p.presetProperties(preset), "modo "+string(preset.ID)))
+	p.recordBundleConflicts(overrideAnime, nil)
 
-	// Escaladores melhores (nativos, sem shader externo pesado)
-	p.mpv.SetPropertyString("scale", "spline36")
-	p.mpv.SetPropertyString("cscale", "spline36")
-	p.mpv.SetPropertyString("dscale", "mitchell")
+	p.currentMode = mode
+	p.animePreset = ""
+	p.lastDecision = nil
 
-	// Debanding leve
-	p.mpv.SetPropertyString("deband", "yes")
-	p.mpv.SetPropertyString("deband-iterations", "2")
-	p.mpv.SetPropertyString("deband-threshold", "35")
-	p.mpv.SetPropertyString("deband-range", "20")
+	p.mu.Unlock()
 
-	// Dithering
-	p.mpv.SetPropertyString("dither-depth", "auto")
+	p.emit(ModeChangedEvent{change})
+	p.showModeBanner(preset, change.Reason)
 
-	// Carregar shader FSR (AMD FidelityFX Super Resolution)
-	fsrPath := filepath.Join(p.shaderPath, "FSR.glsl")
-	err := p.mpv.Command([]string{"change-list", "glsl-shaders", "append", fsrPath})
-	if err != nil {
-		fmt.Printf("  ⚠️ Shader FSR não encontrado: %s\n", fsrPath)
-	} else {
-		fmt.Println("  ✓ AMD FSR ativado (upscaling eficiente)")
+	if len(preset.Upscale) > 0 && p.getInt64("width") > 0 {
+		p.applyResolutionChain()
 	}
 
-	fmt.Println("  ✓ Profile gpu-hq")
-	fmt.Println("  ✓ Escalamento spline36")
-	fmt.Println("  ✓ Debanding leve")
+	return nil
 }
 
-// applyHighMode aplica configurações do modo ultra
-func (p *Player) applyHighMode() {
-	// Backend moderno (Vulkan se disponível)
-	p.mpv.SetPropertyString("vo", "gpu-next")
-	p.mpv.SetPropertyString("profile", "gpu-hq")
-
-	// Hardware decoding com copy-back para processamento
-	p.mpv.SetPropertyString("hwdec", "auto-copy")
-
-	// Escaladores de alta qualidade
-	p.mpv.SetPropertyString("scale", "ewa_lanczossharp")
-	p.mpv.SetPropertyString("cscale", "ewa_lanczossharp")
-	p.mpv.SetPropertyString("dscale", "mitchell")
-
-	// Debanding agressivo
-	p.mpv.SetPropertyString("deband", "yes")
-	p.mpv.SetPropertyString("deband-iterations", "4")
-	p.mpv.SetPropertyString("deband-threshold", "48")
-	p.mpv.SetPropertyString("deband-range", "24")
-	p.mpv.SetPropertyString("deband-grain", "24")
-
-	// Dithering de alta qualidade
-	p.mpv.SetPropertyString("dither-depth", "auto")
-	p.mpv.SetPropertyString("temporal-dither", "yes")
-
-	// HDR tone mapping (se disponível)
-	p.mpv.SetPropertyString("tone-mapping", "bt.2446a")
-	p.mpv.SetPropertyString("tone-mapping-mode", "auto")
-
-	// Carregar shader FSRCNNX (Rede Neural)
-	fsrcnnxPath := filepath.Join(p.shaderPath, "FSRCNNX_x2_16-0-4-1.glsl")
-	err := p.mpv.Command([]string{"change-list", "glsl-shaders", "append", fsrcnnxPath})
-	if err != nil {
-		fmt.Printf("  ⚠️ Shader FSRCNNX não encontrado: %s\n", fsrcnnxPath)
-		// Fallback para Anime4K se FSRCNNX não disponível
-		anime4kPath := filepath.Join(p.shaderPath, "Anime4K_Upscale_CNN_x2_VL.glsl")
-		p.mpv.Command([]string{"change-list", "glsl-shaders", "append", anime4kPath})
-	} else {
-		fmt.Println("  ✓ FSRCNNX Neural Network ativado")
+// applyPreset envia as propriedades e shaders de um preset para o MPV
+func (p *Player) applyPreset(preset *Preset) {
+	for _, prop := range preset.Properties {
+		if err := p.engine.SetPropertyString(prop.Name, prop.Value); err != nil {
+			fmt.Printf("  ⚠️ %s=%s: %v\n", prop.Name, prop.Value, err)
+		}
 	}
 
-	// Opcional: Adicionar sharpening
-	casPath := filepath.Join(p.shaderPath, "CAS.glsl")
-	p.mpv.Command([]string{"change-list", "glsl-shaders", "append", casPath})
+	p.appendShaders(preset.Shaders)
 
-	fmt.Println("  ✓ Backend gpu-next (Vulkan)")
-	fmt.Println("  ✓ Upscaling por Rede Neural")
-	fmt.Println("  ✓ Debanding agressivo")
-	fmt.Println("  ✓ HDR tone mapping")
+	for _, note := range preset.Notes {
+		fmt.Printf("  ✓ %s\n", note)
+	}
 }
 
 // GetCurrentMode retorna o modo atual
@@ -214,10 +129,17 @@ func (p *Player) GetCurrentMode() PerformanceMode {
 }
 
 // AutoSelectMode seleciona automaticamente o modo baseado na GPU
+// Usa o modo recomendado pelo último benchmark (player4k bench)
+// Sem benchmark, retorna Medium como padrão seguro
 func (p *Player) AutoSelectMode() PerformanceMode {
-	// Por enquanto retorna Medium como padrão seguro
-	// TODO: Detectar GPU e selecionar automaticamente
-	return ModeMedium
+	cfg, err := LoadUserConfig()
+	if err != nil || cfg.RecommendedMode == "" {
+		return ModeMedium
+	}
+	if _, ok := GetPreset(cfg.RecommendedMode); !ok {
+		return ModeMedium
+	}
+	return cfg.RecommendedMode
 }
 
 // EnableInterpolation ativa interpolação de movimento (motion smoothing)
@@ -227,45 +149,33 @@ func (p *Player) EnableInterpolation(enable bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	props := PropertyList{{"interpolation", "no"}, {"video-sync", "audio"}}
+	if enable {
+		props = PropertyList{{"interpolation", "yes"}, {"tscale", "oversample"}, {"video-sync", "display-resample"}}
+	}
+	for _, prop := range props {
+		p.engine.SetPropertyString(prop.Name, prop.Value)
+	}
+	p.recordBundleConflicts(overrideInterpolation, p.bundle.Conflicts(props, "interpolação"))
+
 	if enable {
-		p.mpv.SetPropertyString("interpolation", "yes")
-		p.mpv.SetPropertyString("tscale", "oversample")
-		p.mpv.SetPropertyString("video-sync", "display-resample")
 		fmt.Println("✓ Interpolação de movimento ativada")
 	} else {
-		p.mpv.SetPropertyString("interpolation", "no")
-		p.mpv.SetPropertyString("video-sync", "audio")
 		fmt.Println("✓ Interpolação de movimento desativada")
 	}
 }
 
 // SetAnimeMode ativa otimizações específicas para anime
+// Usa a pipeline padrão (Anime4K Modo A HQ); veja SetAnimePreset para as demais
 func (p *Player) SetAnimeMode(enable bool) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if enable {
-		// Limpar shaders anteriores
-		p.mpv.SetPropertyString("glsl-shaders", "")
-
-		// Carregar shaders Anime4K
-		shaders := []string{
-			"Anime4K_Clamp_Highlights.glsl",
-			"Anime4K_Restore_CNN_VL.glsl",
-			"Anime4K_Upscale_CNN_x2_VL.glsl",
-			"Anime4K_AutoDownscalePre_x2.glsl",
-			"Anime4K_AutoDownscalePre_x4.glsl",
-			"Anime4K_Upscale_CNN_x2_M.glsl",
-		}
-
-		for _, shader := range shaders {
-			shaderPath := filepath.Join(p.shaderPath, "Anime4K", shader)
-			p.mpv.Command([]string{"change-list", "glsl-shaders", "append", shaderPath})
-		}
-
-		fmt.Println("🎌 Modo Anime ativado (Anime4K)")
-	} else {
-		// Voltar ao modo atual
-		p.SetPerformanceMode(p.currentMode)
+		p.SetAnimePreset(DefaultAnimePreset)
+		return
 	}
+
+	// Voltar ao modo atual
+	p.mu.Lock()
+	mode := p.currentMode
+	p.mu.Unlock()
+	p.SetPerformanceMode(mode)
 }
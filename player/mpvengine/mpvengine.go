@@ -0,0 +1,191 @@
+// Package mpvengine é o backend do player sobre o libmpv (gen2brain/go-mpv)
+// Importar o pacote registra o backend: import _ ".../player/mpvengine"
+// Fica fora do pacote player para que ele possa ser testado sem o libmpv instalado
+package mpvengine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ThiagoFrag/Goanime-Player4k/internal/mpvabi"
+	"github.com/ThiagoFrag/Goanime-Player4k/player"
+	"github.com/gen2brain/go-mpv"
+)
+
+func init() {
+	player.RegisterEngine(New)
+}
+
+// engine é o adaptador do player.Engine para o libmpv
+// Depois de TerminateDestroy todas as chamadas retornam player.ErrClosed em vez de
+// acessar o handle liberado
+type engine struct {
+	mu        sync.RWMutex
+	m         *mpv.Mpv
+	destroyed bool
+}
+
+// New cria e inicializa uma instância do libmpv
+// As opções são aplicadas antes do Initialize (ex: vo=null para uso sem janela)
+func New(options ...player.Property) (player.Engine, error) {
+	m := mpv.New()
+	if m == nil {
+		return nil, fmt.Errorf("falha ao criar instância MPV")
+	}
+
+	for _, opt := range options {
+		if err := m.SetOptionString(opt.Name, opt.Value); err != nil {
+			m.TerminateDestroy()
+			return nil, fmt.Errorf("opção %s=%s: %w", opt.Name, opt.Value, err)
+		}
+	}
+
+	if err := m.Initialize(); err != nil {
+		m.TerminateDestroy()
+		return nil, fmt.Errorf("falha ao inicializar MPV: %w", err)
+	}
+
+	return &engine{m: m}, nil
+}
+
+// engineError traduz os erros do go-mpv que o player trata
+func engineError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mpv.ErrPropertyUnavailable):
+		return player.ErrPropertyUnavailable
+	case errors.Is(err, mpv.ErrPropertyFormat):
+		return player.ErrPropertyFormat
+	case errors.Is(err, mpv.ErrVoInitFailed):
+		return player.ErrVoInitFailed
+	}
+	return err
+}
+
+func (e *engine) SetOptionString(name, value string) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return player.ErrClosed
+	}
+	return engineError(e.m.SetOptionString(name, value))
+}
+
+func (e *engine) SetPropertyString(name, value string) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return player.ErrClosed
+	}
+	return engineError(e.m.SetPropertyString(name, value))
+}
+
+func (e *engine) SetProperty(name string, format player.Format, data interface{}) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return player.ErrClosed
+	}
+	return engineError(e.m.SetProperty(name, mpv.Format(format), data))
+}
+
+func (e *engine) GetProperty(name string, format player.Format) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return nil, player.ErrClosed
+	}
+	val, err := e.m.GetProperty(name, mpv.Format(format))
+	return val, engineError(err)
+}
+
+func (e *engine) Command(cmd []string) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return player.ErrClosed
+	}
+	return engineError(e.m.Command(cmd))
+}
+
+func (e *engine) ObserveProperty(id uint64, name string, format player.Format) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return player.ErrClosed
+	}
+	return engineError(e.m.ObserveProperty(id, name, mpv.Format(format)))
+}
+
+// WaitEvent aguarda um evento e decodifica o payload antes de devolvê-lo
+func (e *engine) WaitEvent(timeout float64) *player.EngineEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.destroyed {
+		return nil
+	}
+	ev := e.m.WaitEvent(timeout)
+	if ev == nil || ev.EventID == mpv.EventNone {
+		return nil
+	}
+
+	out := &player.EngineEvent{
+		ID:            player.EngineEventID(ev.EventID),
+		Error:         engineError(ev.Error),
+		ReplyUserdata: ev.ReplyUserdata,
+	}
+
+	switch ev.EventID {
+	case mpv.EventPropertyChange:
+		if ev.Data != nil {
+			prop := ev.Property()
+			out.Property = &player.PropertyChange{
+				Name:   prop.Name,
+				Format: player.Format(prop.Format),
+				Data:   prop.Data,
+			}
+		}
+	case mpv.EventEnd:
+		if ev.Data != nil {
+			end := ev.EndFile()
+			out.EndFile = &player.EndFile{
+				Reason: player.EndReason(end.Reason),
+				Error:  engineError(end.Error),
+			}
+		}
+	case mpv.EventClientMessage:
+		out.ClientMessage = mpvabi.ClientMessageArgs(ev.Data)
+	}
+
+	return out
+}
+
+func (e *engine) Wakeup() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.destroyed {
+		e.m.Wakeup()
+	}
+}
+
+// TerminateDestroy libera o handle; chamadas repetidas são ignoradas
+func (e *engine) TerminateDestroy() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.destroyed {
+		return
+	}
+	e.destroyed = true
+	e.m.TerminateDestroy()
+}
@@ -0,0 +1,21 @@
+package player
+
+// platformDefaults guarda as configurações que mudam de um sistema para outro
+// Cada sistema define a variável platform no seu arquivo platform_<os>.go
+type platformDefaults struct {
+	VO         string   // saída de vídeo
+	GPUContext string   // contexto da GPU ("" = automático)
+	GPUAPIs    []string // valores de gpu-api aceitos (veja ConfigBundle.PlatformConflicts)
+	// Padrões de osd.font, subtitles.font e screenshots.directory (veja DefaultSettings)
+	OSDFont       string
+	SubFont       string
+	ScreenshotDir string
+}
+
+// applyPlatformDefaults aplica as configurações específicas do sistema atual
+func (p *Player) applyPlatformDefaults() {
+	p.setBase("vo", platform.VO)
+	if platform.GPUContext != "" {
+		p.setBase("gpu-context", platform.GPUContext)
+	}
+}
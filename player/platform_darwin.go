@@ -0,0 +1,11 @@
+package player
+
+// macOS: MoltenVK e fontes do sistema
+var platform = platformDefaults{
+	VO:            "gpu",
+	GPUAPIs:       []string{"auto", "vulkan", "opengl"},
+	GPUContext:    "macvk",
+	OSDFont:       "Helvetica Neue",
+	SubFont:       "Helvetica Neue Medium",
+	ScreenshotDir: "~~desktop/",
+}
@@ -0,0 +1,11 @@
+package player
+
+// Linux: contexto automático (Wayland/X11) e fontes resolvidas pelo fontconfig
+// ~~desktop nem sempre existe, então as capturas vão para ~/Pictures
+var platform = platformDefaults{
+	VO:            "gpu",
+	GPUAPIs:       []string{"auto", "vulkan", "opengl"},
+	OSDFont:       "Noto Sans",
+	SubFont:       "Noto Sans SemiBold",
+	ScreenshotDir: "~/Pictures/",
+}
@@ -0,0 +1,12 @@
+//go:build !windows && !linux && !darwin
+
+package player
+
+// Outros sistemas (BSDs): deixa o MPV escolher contexto e fonte
+var platform = platformDefaults{
+	VO:            "gpu",
+	GPUAPIs:       []string{"auto", "vulkan", "opengl"},
+	OSDFont:       "sans-serif",
+	SubFont:       "sans-serif",
+	ScreenshotDir: "~/",
+}
@@ -0,0 +1,11 @@
+package player
+
+// Windows: Direct3D 11 e fontes Segoe UI (padrão do sistema)
+var platform = platformDefaults{
+	VO:            "gpu",
+	GPUAPIs:       []string{"auto", "d3d11", "vulkan", "opengl"},
+	GPUContext:    "d3d11",
+	OSDFont:       "Segoe UI",
+	SubFont:       "Segoe UI Semibold",
+	ScreenshotDir: "~~desktop/",
+}
@@ -2,220 +2,229 @@
 package player
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
-	"runtime"
 	"sync"
-
-	"github.com/gen2brain/go-mpv"
+	"time"
 )
 
 // Player representa o player de vídeo com suporte a upscaling
 type Player struct {
-	mpv          *mpv.Mpv
+	engine       Engine
 	mu           sync.Mutex
 	currentMode  PerformanceMode
+	animePreset  AnimePreset
 	windowHandle int64
-	isPlaying    bool
-	isPaused     bool
 	volume       int
-	duration     float64
-	_            float64 // reserved for position
 	shaderPath   string
-
-	// Callbacks para integração com GUI
-	OnTimeUpdate  func(position, duration float64)
-	OnStateChange func(state string)
-	OnError       func(err error)
-	OnFileLoaded  func(filename string)
-	OnModeChanged func(mode PerformanceMode)
-}
-
-// New cria uma nova instância do player
+	shaders      *ShaderManifest
+	shaderReport ShaderReport
+	lastDecision *ChainDecision
+	adaptive     *AdaptiveController
+	lastSample   time.Time
+	langPrefs    *LanguagePreferences
+	series       string
+	titleFormat  string
+	entryTitles  map[string]string // títulos do .m3u (#EXTINF), por arquivo
+	history      *WatchHistory
+	autoResume   bool
+	media        mediaHistory // arquivo atual no histórico (veja history.go)
+	skip         skipState    // trechos para pular (veja skip.go)
+	intros       *IntroCache
+	profiles     *ProfileStore
+	profile      profileState // série do arquivo atual (veja profiles.go)
+	settings     *Settings
+	bundle       *ConfigBundle // pasta de configuração do MPV (veja bundle.go)
+
+	bundleOverrides map[string][]BundleConflict // opções do mpv.conf trocadas pelo Go, por origem
+
+	// Loop de eventos (Run) e encerramento (Close)
+	stopRun context.CancelFunc
+	runDone chan struct{}
+	closed  bool
+
+	// Estado vindo dos observers do MPV (veja properties.go)
+	snapshot       PlaybackSnapshot
+	lastTimeUpdate float64
+	stateIn        stateInputs // sinais da máquina de estados (veja state.go)
+
+	// Eventos para a GUI, RPC e logs (veja Subscribe)
+	events eventBus
+}
+
+// New cria uma nova instância do player usando o libmpv
 func New() (*Player, error) {
-	m := mpv.New()
-	if m == nil {
-		return nil, fmt.Errorf("falha ao criar instância MPV")
+	engine, err := newEngine()
+	if err != nil {
+		return nil, err
 	}
 
-	// Inicializar MPV
-	if err := m.Initialize(); err != nil {
-		return nil, fmt.Errorf("falha ao inicializar MPV: %w", err)
-	}
+	return NewWithEngine(engine), nil
+}
 
+// NewWithEngine cria um player sobre um Engine já inicializado (ex: FakeEngine)
+func NewWithEngine(engine Engine) *Player {
+	return newPlayer(engine, nil)
+}
+
+// newPlayer monta o player; bundle é o pacote que o engine carregou (ou nil)
+func newPlayer(engine Engine, bundle *ConfigBundle) *Player {
 	// Configurar caminho dos shaders
-	execPath, _ := filepath.Abs(".")
-	shaderPath := filepath.Join(execPath, "shaders")
+	shaderPath := DefaultShaderDir()
+
+	manifest, err := LoadShaderManifest(shaderPath)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		manifest = &ShaderManifest{}
+	}
 
 	p := &Player{
-		mpv:         m,
+		engine:      engine,
 		currentMode: ModeLow, // Começa no modo mais leve
 		volume:      100,
+		snapshot:    PlaybackSnapshot{State: StateIdle, Idle: true, Chapter: -1, Volume: 100, PlaylistPos: -1},
 		shaderPath:  shaderPath,
+		shaders:     manifest,
+		skip:        skipState{active: -1, key: defaultSkipKey},
+		bundle:      bundle,
+	}
+
+	// Com pacote, o aviso de pular usa a tecla de skip-segment do input.conf dele
+	if bundle.Active() && bundle.SkipKey != "" {
+		p.skip.key = bundle.SkipKey
 	}
 
 	// Configurações base
 	p.setupBaseConfig()
+	p.observeProperties()
 
-	return p, nil
+	// Verificar shaders na inicialização
+	report := p.VerifyShaders()
+	for _, c := range report.Problems() {
+		if !c.Optional {
+			fmt.Printf("⚠️ Shader %s: %s (%s)\n", c.Status, c.Name, c.Path)
+		}
+	}
+
+	return p
 }
 
 // setupBaseConfig configura opções base do MPV
 func (p *Player) setupBaseConfig() {
 	// === HABILITAR CONTROLES DE TECLADO ===
-	p.mpv.SetPropertyString("input-default-bindings", "yes")
-	p.mpv.SetPropertyString("input-vo-keyboard", "yes")
-	p.mpv.SetOptionString("input-default-bindings", "yes")
-	p.mpv.SetOptionString("input-vo-keyboard", "yes")
+	p.setBase("input-default-bindings", "yes")
+	p.setBase("input-vo-keyboard", "yes")
 
 	// === OSC - ON SCREEN CONTROLLER ===
 	// NOTA: O OSC só funciona se o MPV foi compilado com Lua
-	p.mpv.SetPropertyString("osc", "yes")
-	p.mpv.SetOptionString("osc", "yes")
-	p.mpv.SetPropertyString("load-scripts", "yes")
+	// Com pacote, osc=no do mpv.conf vale (a interface é o uosc)
+	p.setBase("osc", "yes")
+	p.setBase("load-scripts", "yes")
 
 	// Configurações do OSC
-	p.mpv.SetPropertyString("script-opts", "osc-layout=bottombar,osc-seekbarstyle=bar,osc-deadzonesize=0.5,osc-minmousemove=0,osc-hidetimeout=2000,osc-fadeduration=250,osc-showwindowed=yes,osc-showfullscreen=yes,osc-boxalpha=80")
-
-	// Habilitar aceleração de hardware
-	p.mpv.SetPropertyString("hwdec", "auto-safe")
+	p.setBase("script-opts", "osc-layout=bottombar,osc-seekbarstyle=bar,osc-deadzonesize=0.5,osc-minmousemove=0,osc-hidetimeout=2000,osc-fadeduration=250,osc-showwindowed=yes,osc-showfullscreen=yes,osc-boxalpha=80")
 
 	// === CONFIGURAÇÕES DE FPS E SINCRONIZAÇÃO ===
-	p.mpv.SetPropertyString("video-sync", "display-resample")
-	p.mpv.SetPropertyString("interpolation", "yes")
-	p.mpv.SetPropertyString("tscale", "oversample")
-	p.mpv.SetPropertyString("framedrop", "no")
-	p.mpv.SetPropertyString("opengl-swapinterval", "1")
+	p.setBase("framedrop", "no")
+	p.setBase("opengl-swapinterval", "1")
 
 	// Configurações de áudio
-	p.mpv.SetPropertyString("audio-pitch-correction", "yes")
-	p.mpv.SetPropertyString("audio-normalize-downmix", "yes")
-	p.mpv.SetPropertyString("volume-max", "150") // Permite volume até 150%
+	p.setBase("audio-pitch-correction", "yes")
+	p.setBase("audio-normalize-downmix", "yes")
 
 	// === JANELA E VISUAL ===
-	p.mpv.SetPropertyString("keep-open", "yes")
-	p.mpv.SetPropertyString("force-window", "immediate")
-	p.mpv.SetPropertyString("border", "no")            // Sem borda da janela (mais limpo)
-	p.mpv.SetPropertyString("window-maximized", "yes") // Inicia maximizado
+	p.setBase("keep-open", "yes")
+	p.setBase("force-window", "immediate")
+	p.setBase("border", "no")            // Sem borda da janela (mais limpo)
+	p.setBase("window-maximized", "yes") // Inicia maximizado
 
 	// Fundo preto quando pausado/sem vídeo
-	p.mpv.SetPropertyString("background", "#000000")
+	p.setBase("background", "#000000")
 
 	// === OSD CUSTOMIZADO ESTILO ANIME ===
-	// Fonte moderna
-	p.mpv.SetPropertyString("osd-font", "Segoe UI")
-	p.mpv.SetPropertyString("osd-font-size", "36")
-	p.mpv.SetPropertyString("osd-bold", "yes")
-
-	// Cores estilo anime (rosa/roxo gradient feel)
-	p.mpv.SetPropertyString("osd-color", "#FFFFFFFF")        // Texto branco
-	p.mpv.SetPropertyString("osd-border-color", "#FF6B9DFF") // Borda rosa
-	p.mpv.SetPropertyString("osd-border-size", "2.5")
-	p.mpv.SetPropertyString("osd-shadow-color", "#80000000") // Sombra suave
-	p.mpv.SetPropertyString("osd-shadow-offset", "2")
-	p.mpv.SetPropertyString("osd-back-color", "#60000000") // Fundo semi-transparente
-
+	// Fontes, cores e margens vêm de Settings (veja settings.go)
 	// Barra de progresso estilizada
-	p.mpv.SetPropertyString("osd-level", "1")
-	p.mpv.SetPropertyString("osd-duration", "2500")
-	p.mpv.SetPropertyString("osd-bar", "yes")
-	p.mpv.SetPropertyString("osd-bar-align-y", "0.95") // Quase no fundo
-	p.mpv.SetPropertyString("osd-bar-h", "1.5")        // Fina e elegante
-	p.mpv.SetPropertyString("osd-bar-w", "85")         // 85% da largura
+	p.setBase("osd-level", "1")
+	p.setBase("osd-bar-align-y", "0.95") // Quase no fundo
+	p.setBase("osd-bar-h", "1.5")        // Fina e elegante
+	p.setBase("osd-bar-w", "85")         // 85% da largura
 
 	// Mensagens personalizadas
-	p.mpv.SetPropertyString("osd-playing-msg", "▶ ${media-title}")
-	p.mpv.SetPropertyString("osd-status-msg", "${time-pos} / ${duration}  •  ${percent-pos}%")
-
-	// Margens do OSD
-	p.mpv.SetPropertyString("osd-margin-x", "25")
-	p.mpv.SetPropertyString("osd-margin-y", "20")
-
-	// === LEGENDAS ESTILIZADAS ===
-	p.mpv.SetPropertyString("sub-auto", "fuzzy")
-	p.mpv.SetPropertyString("sub-file-paths", "subs:subtitles:Subs:Subtitles:legendas")
-	p.mpv.SetPropertyString("sub-font", "Segoe UI Semibold")
-	p.mpv.SetPropertyString("sub-font-size", "46")
-	p.mpv.SetPropertyString("sub-color", "#FFFFFFFF")
-	p.mpv.SetPropertyString("sub-border-color", "#FF000000")
-	p.mpv.SetPropertyString("sub-border-size", "2.5")
-	p.mpv.SetPropertyString("sub-shadow-color", "#80000000")
-	p.mpv.SetPropertyString("sub-shadow-offset", "1")
-	p.mpv.SetPropertyString("sub-margin-y", "40")
-	p.mpv.SetPropertyString("sub-blur", "0.2") // Leve blur nas bordas
-
-	// === SCREENSHOTS ===
-	p.mpv.SetPropertyString("screenshot-format", "png")
-	p.mpv.SetPropertyString("screenshot-png-compression", "7")
-	p.mpv.SetPropertyString("screenshot-template", "GoAnime_%F_%P")
-	p.mpv.SetPropertyString("screenshot-directory", "~~desktop/")
+	p.setBase("osd-playing-msg", "▶ ${media-title}")
+	p.setBase("osd-status-msg", "${time-pos} / ${duration}  •  ${percent-pos}%")
 
 	// === CONTROLES ADICIONAIS ===
-	p.mpv.SetPropertyString("input-terminal", "yes")
-	p.mpv.SetPropertyString("cursor-autohide", "1500")       // Esconde cursor após 1.5s
-	p.mpv.SetPropertyString("cursor-autohide-fs-only", "no") // Esconde mesmo fora de fullscreen
-	p.mpv.SetPropertyString("input-cursor", "yes")
-
-	// === VELOCIDADE DE REPRODUÇÃO ===
-	p.mpv.SetPropertyString("speed", "1.0")
-
-	// === CACHE PARA STREAMING ===
-	p.mpv.SetPropertyString("cache", "yes")
-	p.mpv.SetPropertyString("demuxer-max-bytes", "150MiB")
-	p.mpv.SetPropertyString("demuxer-max-back-bytes", "75MiB")
-	p.mpv.SetPropertyString("demuxer-readahead-secs", "60") // Buffer de 60s
-
-	// Configuração específica por OS
-	switch runtime.GOOS {
-	case "windows":
-		p.mpv.SetPropertyString("vo", "gpu")
-		p.mpv.SetPropertyString("gpu-context", "d3d11")
-	case "linux":
-		p.mpv.SetPropertyString("vo", "gpu")
-	case "darwin":
-		p.mpv.SetPropertyString("vo", "gpu")
-		p.mpv.SetPropertyString("gpu-context", "macvk")
+	p.setBase("input-terminal", "yes")
+	p.setBase("cursor-autohide", "1500")       // Esconde cursor após 1.5s
+	p.setBase("cursor-autohide-fs-only", "no") // Esconde mesmo fora de fullscreen
+	p.setBase("input-cursor", "yes")
+
+	// === PADRÕES CONFIGURÁVEIS ===
+	// hwdec, interpolação, OSD, legendas, capturas e cache (player4k.json pode trocar)
+	defaults := DefaultSettings()
+	for _, key := range SettingKeys() {
+		for _, prop := range defaults.mpvProperties(key) {
+			p.setBase(prop.Name, prop.Value)
+		}
 	}
+
+	// Configuração específica por OS (vo, gpu-context)
+	p.applyPlatformDefaults()
+	p.reconcileBundle()
 }
 
 // SetTitle define o título da janela do player
 func (p *Player) SetTitle(title string) {
-	p.mpv.SetPropertyString("title", title)
-	p.mpv.SetPropertyString("force-media-title", title)
+	p.engine.SetPropertyString("title", title)
+	p.engine.SetPropertyString("force-media-title", title)
+}
+
+// SetWindowTitle define só o título da janela, sem trocar o título da mídia
+// Aceita propriedades do MPV (ex: "${media-title}"), útil com playlists
+func (p *Player) SetWindowTitle(title string) {
+	p.engine.SetPropertyString("title", title)
 }
 
 // LoadInputConfig carrega arquivo de configuração de atalhos
+// O aviso de pular passa a usar a tecla de skip-segment desse arquivo
 func (p *Player) LoadInputConfig(path string) {
-	p.mpv.SetPropertyString("input-conf", path)
+	p.engine.SetPropertyString("input-conf", path)
+
+	if key := readSkipKey(path); key != "" {
+		p.mu.Lock()
+		p.skip.key = key
+		p.mu.Unlock()
+	}
 }
 
 // LoadScript carrega um script Lua
 func (p *Player) LoadScript(path string) error {
-	return p.mpv.Command([]string{"load-script", path})
+	return p.engine.Command([]string{"load-script", path})
 }
 
 // SetScriptsDir define o diretório de scripts
 func (p *Player) SetScriptsDir(path string) {
-	p.mpv.SetPropertyString("scripts", path)
+	p.engine.SetPropertyString("scripts", path)
 }
 
 // SetFullscreen define se o player deve estar em tela cheia
 func (p *Player) SetFullscreen(fs bool) {
 	if fs {
-		p.mpv.SetPropertyString("fullscreen", "yes")
+		p.engine.SetPropertyString("fullscreen", "yes")
 	} else {
-		p.mpv.SetPropertyString("fullscreen", "no")
+		p.engine.SetPropertyString("fullscreen", "no")
 	}
 }
 
 // SetSpeed define a velocidade de reprodução
 func (p *Player) SetSpeed(speed float64) {
-	p.mpv.SetPropertyString("speed", fmt.Sprintf("%.2f", speed))
+	p.engine.SetPropertyString("speed", fmt.Sprintf("%.2f", speed))
 }
 
 // GetSpeed retorna a velocidade atual
 func (p *Player) GetSpeed() float64 {
-	val, err := p.mpv.GetProperty("speed", mpv.FormatDouble)
+	val, err := p.engine.GetProperty("speed", FormatDouble)
 	if err != nil {
 		return 1.0
 	}
@@ -227,7 +236,7 @@ func (p *Player) GetSpeed() float64 {
 
 // TakeScreenshot tira uma captura de tela
 func (p *Player) TakeScreenshot() {
-	p.mpv.Command([]string{"screenshot"})
+	p.engine.Command([]string{"screenshot"})
 }
 
 // SetWindowHandle define a janela onde o vídeo será renderizado
@@ -236,96 +245,80 @@ func (p *Player) SetWindowHandle(handle int64) {
 	defer p.mu.Unlock()
 
 	p.windowHandle = handle
-	p.mpv.SetProperty("wid", mpv.FormatInt64, handle)
+	p.engine.SetProperty("wid", FormatInt64, handle)
 }
 
 // LoadFile carrega um arquivo de vídeo
+// FileLoadedEvent é publicado pelo loop de eventos quando o MPV termina de abrir
 func (p *Player) LoadFile(path string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	err := p.mpv.Command([]string{"loadfile", path})
-	if err != nil {
-		return fmt.Errorf("erro ao carregar arquivo: %w", err)
+	if err := p.engine.Command([]string{"loadfile", path}); err != nil {
+		err = fmt.Errorf("erro ao carregar arquivo: %w", err)
+		p.emitError(err)
+		return err
 	}
-
-	p.isPlaying = true
-	p.isPaused = false
-
-	if p.OnFileLoaded != nil {
-		p.OnFileLoaded(path)
-	}
-
+	p.setSkipPath(path)
+	p.handleStartFile()
 	return nil
 }
 
 // LoadURL carrega um vídeo de uma URL (streaming)
 func (p *Player) LoadURL(url string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	// Configurar para streaming
-	p.mpv.SetPropertyString("stream-lavf-o", "reconnect=1,reconnect_streamed=1,reconnect_delay_max=5")
+	p.engine.SetPropertyString("stream-lavf-o", "reconnect=1,reconnect_streamed=1,reconnect_delay_max=5")
 
-	err := p.mpv.Command([]string{"loadfile", url})
-	if err != nil {
-		return fmt.Errorf("erro ao carregar URL: %w", err)
+	if err := p.engine.Command([]string{"loadfile", url}); err != nil {
+		err = fmt.Errorf("erro ao carregar URL: %w", err)
+		p.emitError(err)
+		return err
 	}
-
-	p.isPlaying = true
-	p.isPaused = false
-
+	p.setSkipPath(url)
+	p.handleStartFile()
 	return nil
 }
 
 // Play inicia ou retoma a reprodução
 func (p *Player) Play() {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	p.mpv.SetPropertyString("pause", "no")
-	p.isPaused = false
-	p.isPlaying = true
-
-	if p.OnStateChange != nil {
-		p.OnStateChange("playing")
-	}
+	p.setPause(false)
 }
 
 // Pause pausa a reprodução
 func (p *Player) Pause() {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	p.mpv.SetPropertyString("pause", "yes")
-	p.isPaused = true
-
-	if p.OnStateChange != nil {
-		p.OnStateChange("paused")
-	}
+	p.setPause(true)
 }
 
 // TogglePause alterna entre play/pause
+// Usa o estado do MPV, então funciona mesmo depois de ESPAÇO na janela
 func (p *Player) TogglePause() {
-	if p.isPaused {
-		p.Play()
-	} else {
-		p.Pause()
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		p.engine.SetPropertyString("pause", boolToYesNo(!s.Paused))
+		s.Paused = !s.Paused
+	})
+}
+
+// setPause altera a pausa no MPV e já atualiza o estado
+// O observer de pause confirma o valor logo em seguida
+func (p *Player) setPause(paused bool) {
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		p.engine.SetPropertyString("pause", boolToYesNo(paused))
+		s.Paused = paused
+	})
+}
+
+// boolToYesNo converte para o formato de flag do MPV
+func boolToYesNo(b bool) string {
+	if b {
+		return "yes"
 	}
+	return "no"
 }
 
 // Stop para a reprodução
 func (p *Player) Stop() {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	p.mpv.Command([]string{"stop"})
-	p.isPlaying = false
-	p.isPaused = false
-
-	if p.OnStateChange != nil {
-		p.OnStateChange("stopped")
-	}
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		p.engine.Command([]string{"stop"})
+		in.Loading, in.Ended, in.Failed = false, false, false
+		s.Idle, s.EOFReached = true, false
+	})
 }
 
 // Seek vai para uma posição específica (em segundos)
@@ -333,7 +326,7 @@ func (p *Player) Seek(position float64) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.mpv.Command([]string{"seek", fmt.Sprintf("%f", position), "absolute"})
+	p.engine.Command([]string{"seek", fmt.Sprintf("%f", position), "absolute"})
 }
 
 // SeekRelative avança ou retrocede (em segundos)
@@ -341,7 +334,7 @@ func (p *Player) SeekRelative(seconds float64) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.mpv.Command([]string{"seek", fmt.Sprintf("%f", seconds), "relative"})
+	p.engine.Command([]string{"seek", fmt.Sprintf("%f", seconds), "relative"})
 }
 
 // SetVolume define o volume (0-100)
@@ -357,7 +350,7 @@ func (p *Player) SetVolume(volume int) {
 	}
 
 	p.volume = volume
-	p.mpv.SetProperty("volume", mpv.FormatInt64, int64(volume))
+	p.engine.SetProperty("volume", FormatInt64, int64(volume))
 }
 
 // GetVolume retorna o volume atual
@@ -367,124 +360,252 @@ func (p *Player) GetVolume() int {
 
 // ToggleMute alterna mudo
 func (p *Player) ToggleMute() {
-	p.mpv.Command([]string{"cycle", "mute"})
+	p.engine.Command([]string{"cycle", "mute"})
 }
 
 // ToggleFullscreen alterna tela cheia
 func (p *Player) ToggleFullscreen() {
-	p.mpv.Command([]string{"cycle", "fullscreen"})
+	p.engine.Command([]string{"cycle", "fullscreen"})
 }
 
-// SetSubtitleTrack define a trilha de legenda
+// SetSubtitleTrack define a trilha de legenda (IDs vêm de Tracks; 0 desliga)
 func (p *Player) SetSubtitleTrack(id int) {
-	p.mpv.SetProperty("sid", mpv.FormatInt64, int64(id))
+	if id <= 0 {
+		p.engine.SetPropertyString("sid", "no")
+		return
+	}
+	p.engine.SetProperty("sid", FormatInt64, int64(id))
 }
 
-// SetAudioTrack define a trilha de áudio
+// SetAudioTrack define a trilha de áudio (IDs vêm de Tracks; 0 desliga)
 func (p *Player) SetAudioTrack(id int) {
-	p.mpv.SetProperty("aid", mpv.FormatInt64, int64(id))
+	if id <= 0 {
+		p.engine.SetPropertyString("aid", "no")
+		return
+	}
+	p.engine.SetProperty("aid", FormatInt64, int64(id))
 }
 
 // LoadSubtitle carrega um arquivo de legenda externo
 func (p *Player) LoadSubtitle(path string) error {
-	return p.mpv.Command([]string{"sub-add", path})
+	return p.engine.Command([]string{"sub-add", path})
 }
 
 // GetPosition retorna a posição atual em segundos
 func (p *Player) GetPosition() float64 {
-	val, err := p.mpv.GetProperty("time-pos", mpv.FormatDouble)
-	if err != nil {
-		return 0
-	}
-	if pos, ok := val.(float64); ok {
-		return pos
-	}
-	return 0
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.snapshot.Position
 }
 
 // GetDuration retorna a duração total em segundos
 func (p *Player) GetDuration() float64 {
-	val, err := p.mpv.GetProperty("duration", mpv.FormatDouble)
-	if err != nil {
-		return 0
-	}
-	if dur, ok := val.(float64); ok {
-		return dur
-	}
-	return 0
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.snapshot.Duration
 }
 
 // GetDroppedFrames retorna o número de frames perdidos
 func (p *Player) GetDroppedFrames() int64 {
-	val, err := p.mpv.GetProperty("frame-drop-count", mpv.FormatInt64)
-	if err != nil {
-		return 0
-	}
-	if frames, ok := val.(int64); ok {
-		return frames
-	}
-	return 0
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.snapshot.DroppedFrames
 }
 
-// IsPlaying retorna se está reproduzindo
+// IsPlaying retorna se está reproduzindo (inclui buffering e seek sem pausa)
 func (p *Player) IsPlaying() bool {
-	return p.isPlaying && !p.isPaused
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.snapshot.State {
+	case StatePlaying, StateBuffering, StateSeeking:
+		return !p.snapshot.Paused
+	}
+	return false
 }
 
-// IsPaused retorna se está pausado
+// IsPaused retorna se há um arquivo aberto e pausado
 func (p *Player) IsPaused() bool {
-	return p.isPaused
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.snapshot.State {
+	case StatePaused, StateBuffering, StateSeeking:
+		return p.snapshot.Paused
+	}
+	return false
 }
 
-// Run executa o loop de eventos do player
-func (p *Player) Run() {
+// ErrClosed é retornado depois que o player foi fechado
+var ErrClosed = errors.New("player fechado")
+
+// Run executa o loop de eventos do player até:
+//   - ctx ser cancelado (retorna ctx.Err())
+//   - Close ser chamado ou o MPV encerrar (retorna nil)
+//   - um erro fatal, como a saída de vídeo não inicializar
+func (p *Player) Run(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	if p.runDone != nil {
+		p.mu.Unlock()
+		return errors.New("Run já está em execução")
+	}
+	loopCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	p.stopRun = cancel
+	p.runDone = done
+	p.mu.Unlock()
+
+	defer func() {
+		cancel()
+		p.mu.Lock()
+		p.stopRun = nil
+		p.runDone = nil
+		p.mu.Unlock()
+		close(done)
+	}()
+
+	// Cancelar o contexto acorda o WaitEvent na hora
+	stopWakeup := context.AfterFunc(loopCtx, p.engine.Wakeup)
+	defer stopWakeup()
+
 	for {
-		event := p.mpv.WaitEvent(1)
+		if loopCtx.Err() != nil {
+			return ctx.Err()
+		}
+
+		event := p.engine.WaitEvent(1)
+		p.tickAdaptive(time.Now())
+		p.tickHistory(time.Now())
 		if event == nil {
 			continue
 		}
 
-		switch event.EventID {
-		case mpv.EventFileLoaded:
-			p.duration = p.GetDuration()
-			fmt.Printf("📄 Arquivo carregado. Duração: %.2f segundos\n", p.duration)
-
-		case 7: // EventEndFile
-			fmt.Println("🏁 Fim do arquivo")
-			if p.OnStateChange != nil {
-				p.OnStateChange("ended")
+		switch event.ID {
+		case EngineStart:
+			path := p.getString("path")
+			p.handleStartFile()
+			p.applyEntryTitle(path)
+			p.setSkipPath(path)
+
+		case EngineFileLoaded:
+			loaded := FileLoadedEvent{
+				Path:     p.getString("path"),
+				Title:    p.getString("media-title"),
+				Duration: p.getFloat64("duration"),
+				Width:    p.getInt64("width"),
+				Height:   p.getInt64("height"),
+			}
+			p.mu.Lock()
+			if p.adaptive != nil {
+				p.adaptive.Reset()
+			}
+			p.snapshot.Duration = loaded.Duration
+			p.lastTimeUpdate = 0
+			p.mu.Unlock()
+			fmt.Printf("📄 Arquivo carregado. Duração: %.2f segundos\n", loaded.Duration)
+			p.handleFileLoaded()
+			p.applyAutoTitle(loaded.Path)
+			p.emit(loaded)
+			p.applyResolutionChain()
+			p.applyProfile(loaded)
+			p.startHistory(loaded)
+			p.loadSkipSegments(loaded)
+
+		case EngineEnd:
+			if err := p.handleEndFile(event.EndFile); err != nil {
+				return err
 			}
 
-		case mpv.EventShutdown:
+		case EngineShutdown:
+			p.recordHistory(true, false)
+			p.closeProfile()
 			fmt.Println("👋 Player encerrado")
-			return
+			return nil
 
-		case mpv.EventPropertyChange:
+		case EnginePropertyChange:
 			// Monitorar mudanças de propriedades
 			p.handlePropertyChange(event)
+
+		case EngineClientMessage:
+			// script-message do goanime.lua
+			p.handleClientMessage(event.ClientMessage)
 		}
 	}
 }
 
-// handlePropertyChange processa mudanças de propriedades
-func (p *Player) handlePropertyChange(_ *mpv.Event) {
-	// Atualizar posição periodicamente
-	if p.OnTimeUpdate != nil && p.isPlaying {
-		pos := p.GetPosition()
-		dur := p.GetDuration()
-		p.OnTimeUpdate(pos, dur)
+// handleEndFile publica o fim do arquivo com o motivo informado pelo MPV
+// Retorna erro apenas quando não há como continuar (saída de vídeo falhou)
+func (p *Player) handleEndFile(end *EndFile) error {
+	ev := EndedEvent{Reason: "eof"}
+	reason := EndFileEOF
+	if end != nil {
+		reason = end.Reason
+		ev.Reason = end.Reason.String()
+		if end.Reason == EndFileError && end.Error != nil {
+			ev.Error = end.Error.Error()
+		}
 	}
 
-	// Verificar frames perdidos (para auto-downgrade de modo)
-	droppedFrames := p.GetDroppedFrames()
-	if droppedFrames > 30 && p.currentMode == ModeHigh {
-		fmt.Println("⚠️ Muitos frames perdidos! Considere baixar o modo de qualidade.")
+	fmt.Printf("🏁 Fim do arquivo (%s)\n", ev.Reason)
+	if reason == EndFileEOF {
+		if next, ok := p.nextEntry(); ok {
+			fmt.Printf("⏭️ Próximo episódio: %s\n", next.Name())
+		}
+	}
+	if reason != EndFileError && reason != EndFileRedirect {
+		p.recordHistory(true, reason == EndFileEOF)
+	}
+	p.closeProfile()
+	p.handleEndState(reason)
+	p.emit(ev)
+	if ev.Error == "" {
+		return nil
 	}
+
+	err := fmt.Errorf("erro na reprodução: %w", end.Error)
+	p.emitError(err)
+	if errors.Is(end.Error, ErrVoInitFailed) {
+		return err
+	}
+	return nil
 }
 
-// Destroy libera os recursos do player
-func (p *Player) Destroy() {
-	if p.mpv != nil {
-		p.mpv.TerminateDestroy()
+// Quit pede ao MPV para encerrar; Run retorna ao receber o shutdown
+func (p *Player) Quit() {
+	p.engine.Command([]string{"quit"})
+}
+
+// Close para o loop de eventos, espera ele terminar e libera o libmpv
+// Pode ser chamado mais de uma vez e de qualquer goroutine (menos de dentro do Run)
+func (p *Player) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
 	}
+	p.closed = true
+	stop, done := p.stopRun, p.runDone
+	p.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+
+	p.recordHistory(true, false)
+	p.closeProfile()
+	p.engine.TerminateDestroy()
+	return nil
+}
+
+// Destroy libera os recursos do player (o mesmo que Close)
+func (p *Player) Destroy() {
+	p.Close()
 }
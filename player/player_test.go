@@ -0,0 +1,379 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nextEvent espera o próximo evento do tipo T (ignora os outros)
+func nextEvent[T Event](t *testing.T, events <-chan Event) T {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatal("canal de eventos fechado")
+			}
+			if e, ok := ev.(T); ok {
+				return e
+			}
+		case <-timeout:
+			var zero T
+			t.Fatalf("timeout esperando %s", zero.Type())
+		}
+	}
+}
+
+// runPlayer roda o loop de eventos até o fim do teste
+func runPlayer(t *testing.T, p *Player) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestSetupBaseConfig(t *testing.T) {
+	e := NewFakeEngine()
+	NewWithEngine(e)
+
+	want := map[string]string{
+		"keep-open":              "yes",
+		"input-default-bindings": "yes",
+		"osc":                    "yes",
+		"force-window":           "immediate",
+		"osd-bar-w":              "85",
+		"hwdec":                  DefaultSettings().Playback.Hwdec,
+	}
+	for name, value := range want {
+		if got, ok := e.Property(name); !ok || got != value {
+			t.Errorf("%s = %q (definida: %v), want %q", name, got, ok, value)
+		}
+	}
+
+	observed := map[string]bool{}
+	for _, c := range e.Calls() {
+		if c.Kind == "observe" {
+			observed[c.Name] = true
+		}
+	}
+	for _, prop := range observedProperties {
+		if !observed[prop.name] {
+			t.Errorf("propriedade %s não observada", prop.name)
+		}
+	}
+}
+
+func TestSetupBaseConfigBundle(t *testing.T) {
+	e := NewFakeEngine()
+	bundle := &ConfigBundle{Kind: BundlePortable, Dir: t.TempDir(), Options: PropertyList{
+		{"osc", "no"},
+		{"input-default-bindings", "no"},
+		{"deband", "yes"},
+		{"interpolation", "no"},
+	}}
+	p := newPlayer(e, bundle)
+
+	// O que o mpv.conf define não é sobrescrito pelos padrões do Go (osc=no do uosc)
+	for _, name := range []string{"osc", "input-default-bindings", "deband", "interpolation"} {
+		if got, ok := e.Property(name); ok {
+			t.Errorf("%s = %q, want valor do mpv.conf", name, got)
+		}
+	}
+	if got, _ := e.Property("keep-open"); got != "yes" {
+		t.Errorf("keep-open = %q, want yes (fora do mpv.conf)", got)
+	}
+	if c := p.BundleConflicts(); len(c) != 0 {
+		t.Errorf("BundleConflicts sem modo = %+v, want nenhum", c)
+	}
+
+	// O modo e a interpolação trocam opções do mpv.conf e isso fica registrado
+	if err := p.SetPerformanceMode(ModeLow); err != nil {
+		t.Fatal(err)
+	}
+	p.EnableInterpolation(true)
+
+	got := map[string]string{}
+	for _, c := range p.BundleConflicts() {
+		got[c.Property] = c.Source
+	}
+	want := map[string]string{"deband": "modo low", "interpolation": "interpolação"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BundleConflicts\n got: %v\nwant: %v", got, want)
+	}
+
+	// Trocar de modo substitui os conflitos do modo anterior
+	if err := p.SetPerformanceMode(ModeMedium); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range p.BundleConflicts() {
+		if c.Property == "deband" {
+			t.Errorf("deband=yes no modo medium ainda aparece como conflito: %+v", c)
+		}
+	}
+}
+
+func TestParseSkipKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"script-message", "i script-message player4k skip-segment  # Pular\n", "i"},
+		{"script-binding", "i script-binding stats/display-stats\nl script-binding goanime/skip-segment\n", "l"},
+		{"tab e comentário", "# l script-binding goanime/skip-segment\nTAB\tscript-message player4k skip-segment\n", "TAB"},
+		{"religada depois", "i script-message player4k skip-segment\ni show-text \"${media-title}\"\n", ""},
+		{"sem skip-segment", "l ab-loop\n", ""},
+	}
+	for _, tt := range tests {
+		if got := parseSkipKey(strings.NewReader(tt.input)); got != tt.want {
+			t.Errorf("%s: parseSkipKey = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Os input.conf do repositório: i solto, l no pacote (i lá abre as estatísticas)
+	if got := readSkipKey(filepath.Join("..", "input.conf")); got != "i" {
+		t.Errorf("input.conf = %q, want i", got)
+	}
+	bundle, err := LoadConfigBundle(BundlePortable, filepath.Join("..", "mpv", "portable_config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.SkipKey != "l" {
+		t.Errorf("portable_config SkipKey = %q, want l", bundle.SkipKey)
+	}
+}
+
+func TestSkipPromptKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		bundle *ConfigBundle
+		want   string
+	}{
+		{"sem pacote", nil, defaultSkipKey},
+		{"pacote", &ConfigBundle{Kind: BundlePortable, Dir: t.TempDir(), HasInput: true, SkipKey: "l"}, "l"},
+		{"pacote sem skip-segment", &ConfigBundle{Kind: BundlePortable, Dir: t.TempDir(), HasInput: true}, defaultSkipKey},
+	}
+	for _, tt := range tests {
+		e := NewFakeEngine()
+		p := newPlayer(e, tt.bundle)
+		p.skip.segments = []SkipSegment{{Kind: SegmentOpening, Start: 10, End: 100}}
+		e.Reset()
+
+		p.updateSkip(20)
+
+		var bind, button []string
+		for _, cmd := range e.Commands() {
+			switch {
+			case cmd[0] == "define-section":
+				bind = cmd
+			case len(cmd) > 2 && cmd[2] == "goanime-skip-button":
+				button = cmd
+			}
+		}
+		if len(bind) < 3 || !strings.HasPrefix(bind[2], tt.want+" seek 100.000 ") {
+			t.Errorf("%s: define-section = %q, want tecla %s", tt.name, bind, tt.want)
+		}
+		if len(button) < 6 || button[5] != tt.want {
+			t.Errorf("%s: goanime-skip-button = %q, want tecla %s", tt.name, button, tt.want)
+		}
+	}
+}
+
+func TestSetPerformanceMode(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := p.Subscribe(ctx)
+	e.Reset()
+
+	if err := p.SetPerformanceMode(ModeHigh); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetCurrentMode(); got != ModeHigh {
+		t.Errorf("GetCurrentMode = %s, want %s", got, ModeHigh)
+	}
+
+	preset, _ := GetPreset(ModeHigh)
+	for _, prop := range preset.Properties {
+		if got, _ := e.Property(prop.Name); got != prop.Value {
+			t.Errorf("%s = %q, want %q", prop.Name, got, prop.Value)
+		}
+	}
+
+	// Os shaders do modo anterior são limpos antes das propriedades do novo
+	writes := e.PropertyWrites()
+	if len(writes) == 0 || writes[0].Name != "glsl-shaders" || writes[0].Value != "" {
+		t.Errorf("primeira escrita = %+v, want glsl-shaders vazio", writes)
+	}
+
+	changed := nextEvent[ModeChangedEvent](t, events)
+	if changed.Mode != ModeHigh || changed.Reason == "" {
+		t.Errorf("ModeChangedEvent = %+v", changed)
+	}
+
+	if err := p.SetPerformanceMode("ultra"); err == nil {
+		t.Error("SetPerformanceMode com modo desconhecido deveria falhar")
+	}
+	if got := p.GetCurrentMode(); got != ModeHigh {
+		t.Errorf("modo desconhecido trocou o modo para %s", got)
+	}
+}
+
+// useTestShaders aponta o Player para uma pasta com todos os shaders do manifesto (sem hash)
+func useTestShaders(t *testing.T, p *Player) string {
+	t.Helper()
+
+	var m ShaderManifest
+	if err := json.Unmarshal(builtinShaderManifest, &m); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for i, e := range m.Shaders {
+		m.Shaders[i].SHA256 = ""
+		path := filepath.Join(dir, filepath.FromSlash(e.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("//!HOOK MAIN\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p.shaderPath = dir
+	p.shaders = &m
+	return dir
+}
+
+func TestSetAnimeMode(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	dir := useTestShaders(t, p)
+	if err := p.SetPerformanceMode(ModeMedium); err != nil {
+		t.Fatal(err)
+	}
+	modeShaders, _ := e.Property("glsl-shaders")
+	e.Reset()
+
+	// Ligar: limpa os shaders do modo e anexa a cadeia do preset padrão
+	p.SetAnimeMode(true)
+	if got := p.GetAnimePreset(); got != DefaultAnimePreset {
+		t.Errorf("GetAnimePreset = %q, want %q", got, DefaultAnimePreset)
+	}
+
+	pl, _ := GetAnimePipeline(DefaultAnimePreset)
+	var paths []string
+	var want [][]string
+	for _, name := range pl.Shaders {
+		entry, ok := p.shaders.Lookup(name)
+		if !ok {
+			t.Fatalf("shader %s fora do manifesto", name)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		paths = append(paths, path)
+		want = append(want, []string{"change-list", "glsl-shaders", "append", path})
+	}
+	if got := e.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands\n got: %q\nwant: %q", got, want)
+	}
+	writes := e.PropertyWrites()
+	if len(writes) != 1 || writes[0].Name != "glsl-shaders" || writes[0].Value != "" {
+		t.Errorf("PropertyWrites = %+v, want só glsl-shaders vazio", writes)
+	}
+	wantList := strings.Join(paths, string(os.PathListSeparator))
+	if got, _ := e.Property("glsl-shaders"); got != wantList {
+		t.Errorf("glsl-shaders\n got: %q\nwant: %q", got, wantList)
+	}
+
+	// Desligar: volta aos shaders do modo atual, sem preset
+	e.Reset()
+	p.SetAnimeMode(false)
+	if got := p.GetAnimePreset(); got != "" {
+		t.Errorf("GetAnimePreset depois de desligar = %q, want vazio", got)
+	}
+	if got := p.GetCurrentMode(); got != ModeMedium {
+		t.Errorf("GetCurrentMode = %s, want %s", got, ModeMedium)
+	}
+	writes = e.PropertyWrites()
+	if len(writes) == 0 || writes[0].Name != "glsl-shaders" || writes[0].Value != "" {
+		t.Errorf("primeira escrita = %+v, want glsl-shaders vazio", writes)
+	}
+	if got, _ := e.Property("glsl-shaders"); got != modeShaders {
+		t.Errorf("glsl-shaders depois de desligar\n got: %q\nwant: %q", got, modeShaders)
+	}
+	for _, cmd := range e.Commands() {
+		if len(cmd) == 4 && strings.Contains(cmd[3], "Anime4K") {
+			t.Errorf("shader Anime4K anexado depois de desligar: %q", cmd)
+		}
+	}
+}
+
+func TestSetAnimePresetUnknown(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	e.Reset()
+
+	if err := p.SetAnimePreset("D-HQ"); err == nil {
+		t.Error("SetAnimePreset com preset desconhecido deveria falhar")
+	}
+	if calls := e.Calls(); len(calls) != 0 {
+		t.Errorf("preset desconhecido mexeu no engine: %+v", calls)
+	}
+}
+
+func TestRunPropertyChange(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := p.Subscribe(ctx)
+	runPlayer(t, p)
+
+	e.PushEvent(&EngineEvent{ID: EnginePropertyChange, Property: &PropertyChange{Name: "duration", Format: FormatDouble, Data: 1420.0}})
+	e.PushEvent(&EngineEvent{ID: EnginePropertyChange, Property: &PropertyChange{Name: "time-pos", Format: FormatDouble, Data: 61.5}})
+
+	update := nextEvent[TimeUpdateEvent](t, events)
+	for update.Position != 61.5 {
+		update = nextEvent[TimeUpdateEvent](t, events)
+	}
+	if update.Duration != 1420 {
+		t.Errorf("TimeUpdateEvent = %+v, want duração 1420", update)
+	}
+	if got := p.Snapshot().Position; got != 61.5 {
+		t.Errorf("Snapshot().Position = %v, want 61.5", got)
+	}
+}
+
+func TestRunVideoOutputFailure(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	e.PushEvent(&EngineEvent{ID: EngineEnd, EndFile: &EndFile{Reason: EndFileError, Error: ErrVoInitFailed}})
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrVoInitFailed) {
+			t.Errorf("Run = %v, want ErrVoInitFailed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run não retornou com a falha da saída de vídeo")
+	}
+}
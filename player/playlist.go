@@ -0,0 +1,413 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoopMode controla a repetição da playlist
+type LoopMode string
+
+const (
+	LoopNone     LoopMode = "none"     // para no fim da playlist
+	LoopFile     LoopMode = "file"     // repete o episódio atual
+	LoopPlaylist LoopMode = "playlist" // volta ao primeiro depois do último
+)
+
+// PlaylistEntry é um item da playlist do MPV
+type PlaylistEntry struct {
+	Filename string `json:"filename"`
+	Title    string `json:"title,omitempty"`
+	Current  bool   `json:"current"` // item selecionado (tocando ou carregando)
+}
+
+// Name retorna o título do item ou, sem título, o nome do arquivo
+func (e PlaylistEntry) Name() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return filepath.Base(e.Filename)
+}
+
+// Playlist é a fila de episódios, mapeada nos comandos de playlist do MPV
+// O MPV avança sozinho para o próximo item; veja SetAutoAdvance
+type Playlist struct {
+	p *Player
+}
+
+// Playlist retorna a fila de reprodução do player
+func (p *Player) Playlist() *Playlist {
+	return &Playlist{p: p}
+}
+
+// Entries retorna os itens da playlist
+// Vem do observer de playlist; antes do Run, lê direto do MPV
+func (pl *Playlist) Entries() []PlaylistEntry {
+	p := pl.p
+	p.mu.Lock()
+	entries := append([]PlaylistEntry(nil), p.snapshot.Playlist...)
+	p.mu.Unlock()
+
+	if len(entries) > 0 {
+		return entries
+	}
+	entries, err := parsePlaylist(p.getString("playlist"))
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+	return entries
+}
+
+// Position retorna o índice do item atual (-1 se a playlist estiver vazia)
+func (pl *Playlist) Position() int {
+	for i, e := range pl.Entries() {
+		if e.Current {
+			return i
+		}
+	}
+	return -1
+}
+
+// Len retorna o número de itens
+func (pl *Playlist) Len() int {
+	return len(pl.Entries())
+}
+
+// Append adiciona arquivos ao fim da playlist
+// Se nada estiver tocando, o primeiro começa na hora
+func (pl *Playlist) Append(paths ...string) error {
+	for _, path := range paths {
+		if err := pl.p.engine.Command([]string{"loadfile", path, "append-play"}); err != nil {
+			return fmt.Errorf("erro ao adicionar %s à playlist: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// AppendEntries adiciona itens de um .m3u; o título de cada um (#EXTINF) vira o media-title
+func (pl *Playlist) AppendEntries(entries ...PlaylistEntry) error {
+	pl.p.rememberTitles(entries)
+	return pl.Append(entryFilenames(entries)...)
+}
+
+// Insert coloca um arquivo na posição index (o número de itens equivale a Append)
+func (pl *Playlist) Insert(index int, path string) error {
+	n, err := pl.count()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index > n {
+		return fmt.Errorf("índice %d fora da playlist (%d itens)", index, n)
+	}
+	if err := pl.Append(path); err != nil {
+		return err
+	}
+
+	// O snapshot (observer de playlist) atrasa em relação ao MPV: depois de outro
+	// Insert/Append ainda não conta os itens novos. O item entrou no fim da lista atual
+	last, err := pl.count()
+	if err != nil {
+		return err
+	}
+	last--
+	if index == last {
+		return nil
+	}
+	// loadfile insert-at só existe no MPV 0.38+; append + move funciona em todos
+	return pl.p.engine.Command([]string{"playlist-move", strconv.Itoa(last), strconv.Itoa(index)})
+}
+
+// count lê o número de itens direto do MPV (sem esperar o observer)
+func (pl *Playlist) count() (int, error) {
+	val, err := pl.p.engine.GetProperty("playlist-count", FormatInt64)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao ler a playlist: %w", err)
+	}
+	n, _ := val.(int64)
+	return int(n), nil
+}
+
+// Remove tira o item index da playlist (remover o atual pula para o próximo)
+func (pl *Playlist) Remove(index int) error {
+	if err := pl.checkIndex(index); err != nil {
+		return err
+	}
+	return pl.p.engine.Command([]string{"playlist-remove", strconv.Itoa(index)})
+}
+
+// Move leva o item from para a posição to
+func (pl *Playlist) Move(from, to int) error {
+	if err := pl.checkIndex(from); err != nil {
+		return err
+	}
+	if err := pl.checkIndex(to); err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	// playlist-move coloca o item antes de "to"; para descer, o alvo é to+1
+	if to > from {
+		to++
+	}
+	return pl.p.engine.Command([]string{"playlist-move", strconv.Itoa(from), strconv.Itoa(to)})
+}
+
+// Shuffle embaralha a playlist
+func (pl *Playlist) Shuffle() error {
+	return pl.p.engine.Command([]string{"playlist-shuffle"})
+}
+
+// Unshuffle desfaz o último Shuffle
+func (pl *Playlist) Unshuffle() error {
+	return pl.p.engine.Command([]string{"playlist-unshuffle"})
+}
+
+// Clear remove todos os itens menos o que está tocando
+func (pl *Playlist) Clear() error {
+	return pl.p.engine.Command([]string{"playlist-clear"})
+}
+
+// Play toca o item index
+func (pl *Playlist) Play(index int) error {
+	if err := pl.checkIndex(index); err != nil {
+		return err
+	}
+	return pl.p.engine.Command([]string{"playlist-play-index", strconv.Itoa(index)})
+}
+
+// SetLoop define o modo de repetição
+func (pl *Playlist) SetLoop(mode LoopMode) error {
+	file, list := "no", "no"
+	switch mode {
+	case LoopNone:
+	case LoopFile:
+		file = "inf"
+	case LoopPlaylist:
+		list = "inf"
+	default:
+		return fmt.Errorf("modo de repetição desconhecido: %s (none, file ou playlist)", mode)
+	}
+	if err := pl.p.engine.SetPropertyString("loop-file", file); err != nil {
+		return err
+	}
+	return pl.p.engine.SetPropertyString("loop-playlist", list)
+}
+
+// Loop retorna o modo de repetição atual
+func (pl *Playlist) Loop() LoopMode {
+	switch {
+	case isLoopOn(pl.p.getString("loop-file")):
+		return LoopFile
+	case isLoopOn(pl.p.getString("loop-playlist")):
+		return LoopPlaylist
+	}
+	return LoopNone
+}
+
+// isLoopOn interpreta loop-file/loop-playlist ("no", "inf", "yes" ou um número)
+func isLoopOn(value string) bool {
+	return value != "" && value != "no"
+}
+
+// checkIndex valida um índice da playlist
+// Usa playlist-count e não Len: logo depois de um Append o snapshot ainda não tem os itens novos
+func (pl *Playlist) checkIndex(index int) error {
+	n, err := pl.count()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= n {
+		return fmt.Errorf("índice %d fora da playlist (%d itens)", index, n)
+	}
+	return nil
+}
+
+// parsePlaylist decodifica o JSON da propriedade playlist
+func parsePlaylist(s string) ([]PlaylistEntry, error) {
+	var entries []PlaylistEntry
+	if s == "" {
+		return entries, nil
+	}
+	if err := json.Unmarshal([]byte(s), &entries); err != nil {
+		return nil, fmt.Errorf("playlist inválida: %w", err)
+	}
+	return entries, nil
+}
+
+// --- Navegação ---
+
+// LoadFiles troca a playlist pelos arquivos informados e começa pelo primeiro
+func (p *Player) LoadFiles(paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("nenhum arquivo para reproduzir")
+	}
+	if err := p.LoadFile(paths[0]); err != nil {
+		return err
+	}
+	return p.Playlist().Append(paths[1:]...)
+}
+
+// LoadEntries troca a playlist pelos itens de um .m3u e começa pelo primeiro
+// O título de cada item (#EXTINF) vira o media-title quando ele começa a tocar
+func (p *Player) LoadEntries(entries ...PlaylistEntry) error {
+	p.rememberTitles(entries)
+	return p.LoadFiles(entryFilenames(entries)...)
+}
+
+// entryFilenames retorna os arquivos dos itens, na mesma ordem
+func entryFilenames(entries []PlaylistEntry) []string {
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.Filename
+	}
+	return paths
+}
+
+// rememberTitles guarda os títulos dos itens para quando cada um começar
+func (p *Player) rememberTitles(entries []PlaylistEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range entries {
+		if e.Title == "" {
+			continue
+		}
+		if p.entryTitles == nil {
+			p.entryTitles = make(map[string]string)
+		}
+		p.entryTitles[e.Filename] = e.Title
+	}
+}
+
+// entryTitle retorna o título do .m3u de um arquivo
+func (p *Player) entryTitle(path string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	title, ok := p.entryTitles[path]
+	return title, ok
+}
+
+// applyEntryTitle troca o media-title pelo título do .m3u quando um arquivo começa
+// Arquivos sem título voltam ao nome que o MPV detecta
+func (p *Player) applyEntryTitle(path string) {
+	p.mu.Lock()
+	known := len(p.entryTitles) > 0
+	p.mu.Unlock()
+	if !known {
+		return
+	}
+
+	title, _ := p.entryTitle(path)
+	p.engine.SetPropertyString("force-media-title", title)
+}
+
+// Next pula para o próximo episódio
+func (p *Player) Next() error {
+	if err := p.engine.Command([]string{"playlist-next"}); err != nil {
+		return fmt.Errorf("não há próximo item na playlist: %w", err)
+	}
+	return nil
+}
+
+// Previous volta para o episódio anterior
+func (p *Player) Previous() error {
+	if err := p.engine.Command([]string{"playlist-prev"}); err != nil {
+		return fmt.Errorf("não há item anterior na playlist: %w", err)
+	}
+	return nil
+}
+
+// nextEntry retorna o item que o MPV vai tocar quando o atual terminar
+func (p *Player) nextEntry() (PlaylistEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pos, entries := p.snapshot.PlaylistPos, p.snapshot.Playlist
+	if pos < 0 || pos+1 >= len(entries) {
+		return PlaylistEntry{}, false
+	}
+	return entries[pos+1], true
+}
+
+// SetAutoAdvance liga/desliga o avanço automático para o próximo episódio
+// Desligado, o player para no último frame de cada arquivo (keep-open=always)
+func (p *Player) SetAutoAdvance(enable bool) {
+	if enable {
+		p.engine.SetPropertyString("keep-open", "yes")
+	} else {
+		p.engine.SetPropertyString("keep-open", "always")
+	}
+}
+
+// --- Arquivos .m3u/.m3u8 ---
+
+// IsPlaylistFile indica se o caminho é uma playlist local (.m3u/.m3u8)
+// Playlists HLS (#EXT-X-...) e URLs continuam indo direto para o MPV
+func IsPlaylistFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if (ext != ".m3u" && ext != ".m3u8") || strings.Contains(path, "://") {
+		return false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasPrefix(strings.TrimSpace(scanner.Text()), "#EXT-X-") {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadM3U lê uma playlist .m3u/.m3u8
+// Caminhos relativos são resolvidos a partir da pasta da playlist; #EXTINF vira o título
+func ReadM3U(path string) ([]PlaylistEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir := filepath.Dir(path)
+	var entries []PlaylistEntry
+	title := ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:<duração>,<título>
+			if _, t, ok := strings.Cut(line, ","); ok {
+				title = strings.TrimSpace(t)
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		if !strings.Contains(line, "://") && !filepath.IsAbs(line) {
+			line = filepath.Join(dir, filepath.FromSlash(line))
+		}
+		entries = append(entries, PlaylistEntry{Filename: line, Title: title})
+		title = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,140 @@
+package player
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakePlaylist faz o FakeEngine contar os loadfile em playlist-count, como o MPV
+// O snapshot (observer) nunca é atualizado, como logo depois de um comando
+func fakePlaylist(e *FakeEngine, count int64) {
+	e.SetValue("playlist-count", count)
+	e.CommandHook = func(cmd []string) error {
+		if cmd[0] == "loadfile" {
+			count++
+			e.SetValue("playlist-count", count)
+		}
+		return nil
+	}
+}
+
+func TestPlaylistInsert(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	fakePlaylist(e, 3)
+	e.Reset()
+
+	pl := p.Playlist()
+	if err := pl.Insert(1, "b.mkv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.Insert(1, "a.mkv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.Insert(5, "z.mkv"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"loadfile", "b.mkv", "append-play"},
+		{"playlist-move", "3", "1"},
+		{"loadfile", "a.mkv", "append-play"},
+		{"playlist-move", "4", "1"},
+		{"loadfile", "z.mkv", "append-play"},
+	}
+	if got := e.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Insert\n got: %q\nwant: %q", got, want)
+	}
+
+	if err := pl.Insert(7, "fora.mkv"); err == nil {
+		t.Error("Insert além do fim da playlist deveria falhar")
+	}
+}
+
+func TestPlaylistAfterAppend(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	fakePlaylist(e, 1)
+
+	// Os itens recém-adicionados já valem para Play/Move/Remove, antes do observer
+	pl := p.Playlist()
+	if err := pl.Append("ep02.mkv", "ep03.mkv"); err != nil {
+		t.Fatal(err)
+	}
+	e.Reset()
+
+	if err := pl.Play(2); err != nil {
+		t.Errorf("Play(2) depois do Append: %v", err)
+	}
+	if err := pl.Move(2, 1); err != nil {
+		t.Errorf("Move(2, 1) depois do Append: %v", err)
+	}
+	if err := pl.Remove(1); err != nil {
+		t.Errorf("Remove(1) depois do Append: %v", err)
+	}
+	want := [][]string{
+		{"playlist-play-index", "2"},
+		{"playlist-move", "2", "1"},
+		{"playlist-remove", "1"},
+	}
+	if got := e.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands\n got: %q\nwant: %q", got, want)
+	}
+
+	e.Reset()
+	for name, err := range map[string]error{
+		"Play(3)":    pl.Play(3),
+		"Move(0, 3)": pl.Move(0, 3),
+		"Remove(-1)": pl.Remove(-1),
+	} {
+		if err == nil {
+			t.Errorf("%s fora da playlist deveria falhar", name)
+		}
+	}
+	if cmds := e.Commands(); len(cmds) != 0 {
+		t.Errorf("índice inválido enviou comandos: %q", cmds)
+	}
+}
+
+func TestPlaylistCountError(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+
+	// Sem playlist-count o erro do MPV volta em vez de "fora da playlist"
+	err := p.Playlist().Play(0)
+	if !errors.Is(err, ErrPropertyUnavailable) {
+		t.Errorf("Play sem playlist-count = %v, want %v", err, ErrPropertyUnavailable)
+	}
+}
+
+func TestLoadEntriesTitles(t *testing.T) {
+	dir := t.TempDir()
+	m3u := filepath.Join(dir, "temporada1.m3u")
+	content := "#EXTM3U\n#EXTINF:1420,Frieren - 01 - O Fim da Jornada\nep01.mkv\nep02.mkv\n"
+	if err := os.WriteFile(m3u, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadM3U(m3u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	if err := p.LoadEntries(entries...); err != nil {
+		t.Fatal(err)
+	}
+
+	ep1, ep2 := filepath.Join(dir, "ep01.mkv"), filepath.Join(dir, "ep02.mkv")
+	p.applyEntryTitle(ep1)
+	if got, _ := e.Property("force-media-title"); got != "Frieren - 01 - O Fim da Jornada" {
+		t.Errorf("force-media-title = %q, want o título do #EXTINF", got)
+	}
+	p.applyEntryTitle(ep2)
+	if got, _ := e.Property("force-media-title"); got != "" {
+		t.Errorf("force-media-title = %q, want vazio (item sem #EXTINF)", got)
+	}
+}
@@ -0,0 +1,289 @@
+package player
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Presets embutidos (low, medium, high) usados quando não há arquivos externos
+//
+//go:embed presets/*.json
+var builtinPresetFiles embed.FS
+
+// Property é um par nome/valor de propriedade do MPV
+type Property struct {
+	Name  string
+	Value string
+}
+
+// PropertyList é uma lista ordenada de propriedades
+// No JSON é um objeto comum, mas a ordem das chaves do arquivo é preservada
+// (ex: "profile" precisa ser aplicado antes de "scale")
+type PropertyList []Property
+
+// UnmarshalJSON lê o objeto mantendo a ordem das chaves
+func (l *PropertyList) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("properties deve ser um objeto")
+	}
+
+	var list PropertyList
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+
+		var raw interface{}
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("propriedade %q: %w", name, err)
+		}
+
+		var value string
+		switch v := raw.(type) {
+		case string:
+			value = v
+		case bool:
+			value = "no"
+			if v {
+				value = "yes"
+			}
+		case float64, json.Number:
+			value = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("propriedade %q: valor deve ser texto, número ou booleano", name)
+		}
+
+		list = append(list, Property{Name: name, Value: value})
+	}
+
+	*l = list
+	return nil
+}
+
+// MarshalJSON escreve o objeto na mesma ordem da lista
+func (l PropertyList) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range l {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(prop.Name)
+		value, _ := json.Marshal(prop.Value)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Preset descreve um modo de qualidade de forma declarativa
+type Preset struct {
+	ID          PerformanceMode `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Icon        string          `json:"icon"`
+	GPURequired string          `json:"gpuRequired"`
+	GPUTier     int             `json:"gpuTier"`
+	Properties  PropertyList    `json:"properties"`
+	Shaders     []string        `json:"shaders"`
+	Upscale     []UpscaleRule   `json:"upscale,omitempty"`
+	Notes       []string        `json:"notes"`
+
+	// Source indica de onde o preset veio ("builtin" ou caminho do arquivo)
+	Source string `json:"-"`
+}
+
+// Info retorna as informações resumidas do preset
+func (pr *Preset) Info() ModeInfo {
+	return ModeInfo{
+		ID:          pr.ID,
+		Name:        pr.Name,
+		Description: pr.Description,
+		Icon:        pr.Icon,
+		GPURequired: pr.GPURequired,
+		GPUTier:     pr.GPUTier,
+	}
+}
+
+// validate verifica os campos obrigatórios do preset
+func (pr *Preset) validate() error {
+	if pr.ID == "" {
+		return fmt.Errorf("campo \"id\" obrigatório")
+	}
+	if strings.ContainsAny(string(pr.ID), " \t/\\") {
+		return fmt.Errorf("id %q inválido (sem espaços ou barras)", pr.ID)
+	}
+	if pr.Name == "" {
+		return fmt.Errorf("preset %s: campo \"name\" obrigatório", pr.ID)
+	}
+	for _, prop := range pr.Properties {
+		if prop.Name == "" {
+			return fmt.Errorf("preset %s: propriedade sem nome", pr.ID)
+		}
+	}
+	for _, r := range pr.Upscale {
+		if r.MinScale < 0 || (r.MaxScale != 0 && r.MaxScale <= r.MinScale) {
+			return fmt.Errorf("preset %s: regra de upscale %q com faixa inválida", pr.ID, r.Label)
+		}
+	}
+	return nil
+}
+
+// parsePreset decodifica e valida um preset em JSON
+func parsePreset(data []byte, source string) (*Preset, error) {
+	var pr Preset
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if err := pr.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	pr.Source = source
+	return &pr, nil
+}
+
+// presetRegistry guarda os presets conhecidos, indexados pelo ID
+type presetRegistry struct {
+	mu      sync.RWMutex
+	presets map[PerformanceMode]*Preset
+}
+
+var (
+	presets         = newPresetRegistry()
+	userPresetsOnce sync.Once
+)
+
+// newPresetRegistry cria o registro já com os presets embutidos
+func newPresetRegistry() *presetRegistry {
+	r := &presetRegistry{presets: make(map[PerformanceMode]*Preset)}
+
+	entries, _ := builtinPresetFiles.ReadDir("presets")
+	for _, entry := range entries {
+		data, err := builtinPresetFiles.ReadFile("presets/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		pr, err := parsePreset(data, "builtin")
+		if err != nil {
+			// Preset embutido inválido é erro de compilação do projeto
+			panic(err)
+		}
+		r.presets[pr.ID] = pr
+	}
+
+	return r
+}
+
+func (r *presetRegistry) add(pr *Preset) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.presets[pr.ID] = pr
+}
+
+func (r *presetRegistry) get(mode PerformanceMode) (*Preset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.presets[mode]
+	return pr, ok
+}
+
+// all retorna os presets ordenados por tier de GPU e depois por ID
+func (r *presetRegistry) all() []*Preset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*Preset, 0, len(r.presets))
+	for _, pr := range r.presets {
+		list = append(list, pr)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].GPUTier != list[j].GPUTier {
+			return list[i].GPUTier < list[j].GPUTier
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// DefaultPresetsDir retorna a pasta "presets" ao lado do executável
+func DefaultPresetsDir() string {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "presets"
+	}
+	return filepath.Join(filepath.Dir(execPath), "presets")
+}
+
+// LoadPresets carrega todos os presets *.json de uma pasta
+// Presets com o mesmo ID de um já existente o substituem
+// Arquivos inválidos são ignorados e reportados no erro retornado
+func LoadPresets(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		pr, err := parsePreset(data, file)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		presets.add(pr)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("presets inválidos: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ensureUserPresets carrega (uma única vez) os presets da pasta padrão
+func ensureUserPresets() {
+	userPresetsOnce.Do(func() {
+		dir := DefaultPresetsDir()
+		if _, err := os.Stat(dir); err != nil {
+			return
+		}
+		if err := LoadPresets(dir); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+	})
+}
+
+// GetPreset retorna o preset de um modo
+func GetPreset(mode PerformanceMode) (*Preset, bool) {
+	ensureUserPresets()
+	return presets.get(mode)
+}
+
+// GetAllPresets retorna todos os presets carregados
+func GetAllPresets() []*Preset {
+	ensureUserPresets()
+	return presets.all()
+}
@@ -0,0 +1,103 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProbeResult descreve um arquivo sem reproduzi-lo
+type ProbeResult struct {
+	Path       string    `json:"path"`
+	Title      string    `json:"title"`
+	Format     string    `json:"format"`
+	Duration   float64   `json:"duration"`
+	Width      int64     `json:"width"`
+	Height     int64     `json:"height"`
+	FPS        float64   `json:"fps"`
+	VideoCodec string    `json:"videoCodec"`
+	Tracks     []Track   `json:"tracks"`
+	Chapters   []Chapter `json:"chapters"`
+}
+
+// probeOptions deixam o MPV sem janela, sem áudio e sem scripts
+var probeOptions = []Property{
+	{"vo", "null"},
+	{"ao", "null"},
+	{"pause", "yes"},
+	{"force-window", "no"},
+	{"load-scripts", "no"},
+	{"osc", "no"},
+	{"input-terminal", "no"},
+	{"terminal", "no"},
+}
+
+// Probe abre o arquivo numa instância MPV sem janela e lê faixas e capítulos
+func Probe(path string, timeout time.Duration) (*ProbeResult, error) {
+	engine, err := newEngine(probeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	defer engine.TerminateDestroy()
+
+	return ProbeWithEngine(engine, path, timeout)
+}
+
+// ProbeWithEngine faz o probe usando um Engine já inicializado (ex: FakeEngine)
+func ProbeWithEngine(engine Engine, path string, timeout time.Duration) (*ProbeResult, error) {
+	if err := engine.Command([]string{"loadfile", path}); err != nil {
+		return nil, fmt.Errorf("erro ao carregar arquivo: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("tempo esgotado abrindo %s", path)
+		}
+
+		ev := engine.WaitEvent(0.1)
+		if ev == nil {
+			continue
+		}
+		if ev.ID == EngineFileLoaded {
+			break
+		}
+		if ev.ID == EngineEnd && ev.EndFile != nil && ev.EndFile.Reason == EndFileError {
+			if ev.EndFile.Error != nil {
+				return nil, fmt.Errorf("não foi possível abrir %s: %w", path, ev.EndFile.Error)
+			}
+			return nil, errors.New("não foi possível abrir " + path)
+		}
+	}
+
+	r := &ProbeResult{
+		Path:       path,
+		Title:      engineString(engine, "media-title"),
+		Format:     engineString(engine, "file-format"),
+		VideoCodec: engineString(engine, "video-codec"),
+	}
+
+	if val, err := engine.GetProperty("duration", FormatDouble); err == nil {
+		r.Duration, _ = val.(float64)
+	}
+	if val, err := engine.GetProperty("container-fps", FormatDouble); err == nil {
+		r.FPS, _ = val.(float64)
+	}
+
+	var err error
+	if r.Tracks, err = parseTrackList(engineString(engine, "track-list")); err != nil {
+		return nil, err
+	}
+	if r.Chapters, err = parseChapterList(engineString(engine, "chapter-list")); err != nil {
+		return nil, err
+	}
+
+	// Com vo=null width/height podem não existir ainda; usa a faixa de vídeo
+	for _, t := range r.Tracks {
+		if t.Type == "video" && (t.Selected || r.Width == 0) {
+			r.Width, r.Height = t.Width, t.Height
+		}
+	}
+
+	return r, nil
+}
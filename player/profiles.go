@@ -0,0 +1,396 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SeriesProfile são os ajustes lembrados de uma série
+// Campos vazios não são aplicados (o arquivo fica com o que já estiver em uso)
+type SeriesProfile struct {
+	Series      string          `json:"series"` // nome da série ou ID informado pelo GoAnimeGUI
+	Mode        PerformanceMode `json:"mode,omitempty"`
+	AnimePreset AnimePreset     `json:"animePreset,omitempty"`
+	SubDelay    float64         `json:"subDelay,omitempty"`  // segundos
+	AudioLang   string          `json:"audioLang,omitempty"` // idioma da faixa de áudio escolhida
+	Volume      int             `json:"volume,omitempty"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// Summary descreve o perfil numa linha (logs e "player4k profiles list")
+func (sp SeriesProfile) Summary() string {
+	var parts []string
+	if sp.Mode != "" {
+		parts = append(parts, "modo "+string(sp.Mode))
+	}
+	if sp.AnimePreset != "" {
+		parts = append(parts, "Anime4K "+string(sp.AnimePreset))
+	}
+	if sp.AudioLang != "" {
+		parts = append(parts, "áudio "+sp.AudioLang)
+	}
+	if sp.SubDelay != 0 {
+		parts = append(parts, fmt.Sprintf("atraso da legenda %+.3fs", sp.SubDelay))
+	}
+	if sp.Volume > 0 {
+		parts = append(parts, fmt.Sprintf("volume %d", sp.Volume))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ProfileStore guarda os perfis por série (profiles.json)
+type ProfileStore struct {
+	mu       sync.Mutex
+	path     string
+	profiles map[string]*SeriesProfile // chave: profileKey da série
+	dirty    bool
+}
+
+// DefaultProfilesPath retorna o caminho do profiles.json na pasta de configuração
+func DefaultProfilesPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles.json"), nil
+}
+
+// OpenProfileStore lê os perfis (vazio se o arquivo não existir)
+func OpenProfileStore(path string) (*ProfileStore, error) {
+	s := &ProfileStore{path: path, profiles: make(map[string]*SeriesProfile)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*SeriesProfile
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, sp := range list {
+		if key := profileKey(sp.Series); key != "" {
+			s.profiles[key] = sp
+		}
+	}
+	return s, nil
+}
+
+// OpenDefaultProfileStore abre o profiles.json da pasta de configuração
+func OpenDefaultProfileStore() (*ProfileStore, error) {
+	path, err := DefaultProfilesPath()
+	if err != nil {
+		return nil, err
+	}
+	return OpenProfileStore(path)
+}
+
+// profileKey normaliza o nome da série (sem diferenciar maiúsculas)
+func profileKey(series string) string {
+	return strings.ToLower(strings.TrimSpace(series))
+}
+
+// Get retorna o perfil de uma série
+func (s *ProfileStore) Get(series string) (SeriesProfile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sp, ok := s.profiles[profileKey(series)]
+	if !ok {
+		return SeriesProfile{}, false
+	}
+	return *sp, true
+}
+
+// List retorna todos os perfis em ordem alfabética
+func (s *ProfileStore) List() []SeriesProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]SeriesProfile, 0, len(s.profiles))
+	for _, sp := range s.profiles {
+		list = append(list, *sp)
+	}
+	sort.Slice(list, func(i, j int) bool { return profileKey(list[i].Series) < profileKey(list[j].Series) })
+	return list
+}
+
+// Update altera (ou cria) o perfil de uma série e retorna o resultado; Save persiste
+func (s *ProfileStore) Update(series string, change func(*SeriesProfile)) SeriesProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := profileKey(series)
+	sp, ok := s.profiles[key]
+	if !ok {
+		sp = &SeriesProfile{Series: strings.TrimSpace(series)}
+		s.profiles[key] = sp
+	}
+	change(sp)
+	sp.UpdatedAt = time.Now()
+	s.dirty = true
+	return *sp
+}
+
+// Reset apaga o perfil de uma série (false se não existia)
+func (s *ProfileStore) Reset(series string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := profileKey(series)
+	if _, ok := s.profiles[key]; !ok {
+		return false
+	}
+	delete(s.profiles, key)
+	s.dirty = true
+	return true
+}
+
+// ResetAll apaga todos os perfis e retorna quantos havia
+func (s *ProfileStore) ResetAll() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.profiles)
+	if n > 0 {
+		s.profiles = make(map[string]*SeriesProfile)
+		s.dirty = true
+	}
+	return n
+}
+
+// Export escreve os perfis em JSON (o mesmo formato do profiles.json)
+func (s *ProfileStore) Export(w io.Writer) error {
+	data, err := json.MarshalIndent(s.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Save grava o profiles.json se algo mudou
+func (s *ProfileStore) Save() error {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	data, err := json.MarshalIndent(s.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// --- Integração com o Player ---
+
+// profileState é a série do arquivo aberto e os ajustes em uso
+// As mudanças vindas do MPV são comparadas com current: só o que o usuário
+// trocou durante a reprodução vai para o perfil
+type profileState struct {
+	series  string
+	current SeriesProfile
+	ready   bool // perfil aplicado; mudanças a partir daqui são gravadas
+}
+
+// SetProfileStore liga os perfis por série (nil desliga)
+func (p *Player) SetProfileStore(s *ProfileStore) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.profiles = s
+}
+
+// ProfileStore retorna os perfis em uso (nil se desligados)
+func (p *Player) ProfileStore() *ProfileStore {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.profiles
+}
+
+// CurrentProfile retorna o perfil da série do arquivo atual
+func (p *Player) CurrentProfile() (SeriesProfile, bool) {
+	p.mu.Lock()
+	store, series := p.profiles, p.profile.series
+	p.mu.Unlock()
+
+	if store == nil || series == "" {
+		return SeriesProfile{}, false
+	}
+	return store.Get(series)
+}
+
+// SetSubtitleDelay define o atraso da legenda em segundos (negativo adianta)
+func (p *Player) SetSubtitleDelay(seconds float64) {
+	p.engine.SetProperty("sub-delay", FormatDouble, seconds)
+}
+
+// GetSubtitleDelay retorna o atraso da legenda em segundos
+func (p *Player) GetSubtitleDelay() float64 {
+	return p.getFloat64("sub-delay")
+}
+
+// profileSeries é a identidade da série de um arquivo:
+// a informada por SetSeries (nome ou ID do GoAnimeGUI) ou a do nome do arquivo
+func (p *Player) profileSeries(path string) string {
+	p.mu.Lock()
+	series := p.series
+	p.mu.Unlock()
+
+	if strings.TrimSpace(series) != "" {
+		return series
+	}
+	if strings.Contains(path, "://") {
+		path = stripURLTokens(path)
+	}
+	return ParseReleasePath(path).Series
+}
+
+// applyProfile aplica o perfil da série quando um arquivo termina de abrir
+// Também escolhe áudio e legenda: o idioma de áudio do perfil vem antes das preferências
+func (p *Player) applyProfile(loaded FileLoadedEvent) {
+	p.mu.Lock()
+	store := p.profiles
+	p.mu.Unlock()
+
+	series := ""
+	if store != nil {
+		series = p.profileSeries(loaded.Path)
+	}
+	prof, found := SeriesProfile{}, false
+	if series != "" {
+		prof, found = store.Get(series)
+	}
+
+	if found {
+		if prof.Mode != "" && (prof.Mode != p.GetCurrentMode() || prof.AnimePreset != p.GetAnimePreset()) {
+			if err := p.SetPerformanceMode(prof.Mode); err != nil {
+				fmt.Printf("⚠️ Perfil de %s: %v\n", prof.Series, err)
+			}
+		}
+		if prof.AnimePreset != "" && prof.AnimePreset != p.GetAnimePreset() {
+			if err := p.SetAnimePreset(prof.AnimePreset); err != nil {
+				fmt.Printf("⚠️ Perfil de %s: %v\n", prof.Series, err)
+			}
+		}
+		if prof.Volume > 0 {
+			p.SetVolume(prof.Volume)
+		}
+	}
+
+	// O atraso é do arquivo, não do player: volta a 0 em séries sem atraso salvo
+	if prof.SubDelay != 0 || p.GetSubtitleDelay() != 0 {
+		p.SetSubtitleDelay(prof.SubDelay)
+	}
+
+	p.applyTrackPreferences(loaded.Title, prof.AudioLang)
+
+	if series == "" {
+		return
+	}
+
+	current := SeriesProfile{
+		Series:      series,
+		Mode:        p.GetCurrentMode(),
+		AnimePreset: p.GetAnimePreset(),
+		SubDelay:    p.GetSubtitleDelay(),
+		Volume:      p.GetVolume(),
+	}
+	if tracks, err := parseTrackList(p.getString("track-list")); err == nil {
+		current.AudioLang = selectedAudioLang(tracks)
+	}
+
+	p.mu.Lock()
+	p.profile = profileState{series: series, current: current, ready: true}
+	p.mu.Unlock()
+
+	if found {
+		fmt.Printf("📺 Perfil de %s: %s\n", prof.Series, prof.Summary())
+		p.emit(ProfileEvent{Action: "applied", Profile: prof})
+	}
+}
+
+// recordProfile grava no perfil da série uma mudança feita durante a reprodução
+func (p *Player) recordProfile(change func(*SeriesProfile)) {
+	p.mu.Lock()
+	store, state := p.profiles, p.profile
+	if store == nil || !state.ready {
+		p.mu.Unlock()
+		return
+	}
+	change(&p.profile.current)
+	p.mu.Unlock()
+
+	prof := store.Update(state.series, change)
+	p.emit(ProfileEvent{Action: "updated", Profile: prof})
+}
+
+// profileChange compara um valor observado com o perfil em uso (chamado com p.mu travado)
+// Retorna a mudança a gravar com recordProfile, ou nil
+func (p *Player) profileChange(prop *PropertyChange, tracks []Track) func(*SeriesProfile) {
+	if p.profiles == nil || !p.profile.ready {
+		return nil
+	}
+	cur := p.profile.current
+
+	switch prop.Name {
+	case "sub-delay":
+		delay := math.Round(propDouble(prop.Data)*1000) / 1000
+		if prop.Data != nil && delay != cur.SubDelay {
+			return func(sp *SeriesProfile) { sp.SubDelay = delay }
+		}
+	case "volume":
+		volume := p.volume
+		if prop.Data != nil && volume != cur.Volume {
+			return func(sp *SeriesProfile) { sp.Volume = volume }
+		}
+	case "track-list":
+		lang := selectedAudioLang(tracks)
+		if lang != "" && lang != cur.AudioLang {
+			return func(sp *SeriesProfile) { sp.AudioLang = lang }
+		}
+	}
+	return nil
+}
+
+// selectedAudioLang é o idioma da faixa de áudio selecionada ("" se não houver)
+func selectedAudioLang(tracks []Track) string {
+	if t := currentAudio(tracks); t != nil && t.Selected {
+		return t.Lang
+	}
+	return ""
+}
+
+// closeProfile para de acompanhar o arquivo e grava os perfis alterados
+func (p *Player) closeProfile() {
+	p.mu.Lock()
+	store := p.profiles
+	p.profile.ready = false
+	p.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+	if err := store.Save(); err != nil {
+		fmt.Printf("⚠️ Não foi possível salvar os perfis: %v\n", err)
+	}
+}
@@ -0,0 +1,257 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// observedProperties são as propriedades acompanhadas via ObserveProperty
+// O ID de cada uma é a posição na lista + 1
+var observedProperties = []struct {
+	name   string
+	format Format
+}{
+	{"time-pos", FormatDouble},
+	{"pause", FormatFlag},
+	{"duration", FormatDouble},
+	{"track-list", FormatString}, // JSON
+	{"chapter", FormatInt64},
+	{"paused-for-cache", FormatFlag},
+	{"demuxer-cache-state", FormatString}, // JSON
+	{"eof-reached", FormatFlag},
+	{"volume", FormatDouble},
+	{"frame-drop-count", FormatInt64},
+	{"seeking", FormatFlag},
+	{"idle-active", FormatFlag},
+	{"playlist", FormatString}, // JSON
+	{"sub-delay", FormatDouble},
+}
+
+// timeUpdateStep é o avanço mínimo da posição para publicar TimeUpdateEvent
+// (time-pos muda a cada frame; a GUI não precisa de mais que 4 atualizações/s)
+const timeUpdateStep = 0.25
+
+// droppedFramesWarning é quantos frames perdidos disparam o aviso no modo High
+const droppedFramesWarning = 30
+
+// PlaybackSnapshot é o estado da reprodução montado a partir dos observers do MPV
+type PlaybackSnapshot struct {
+	State         PlayerState     `json:"state"`
+	Position      float64         `json:"position"`
+	Duration      float64         `json:"duration"`
+	Paused        bool            `json:"paused"`
+	Chapter       int64           `json:"chapter"` // -1 sem capítulos
+	Buffering     bool            `json:"buffering"`
+	Seeking       bool            `json:"seeking"`
+	Idle          bool            `json:"idle"`          // idle-active: nenhum arquivo aberto
+	CacheDuration float64         `json:"cacheDuration"` // segundos à frente já no cache
+	CacheBytes    int64           `json:"cacheBytes"`
+	EOFReached    bool            `json:"eofReached"`
+	Volume        float64         `json:"volume"`
+	DroppedFrames int64           `json:"droppedFrames"`
+	Tracks        []Track         `json:"tracks"`
+	Playlist      []PlaylistEntry `json:"playlist"`
+	PlaylistPos   int             `json:"playlistPos"` // -1 com a playlist vazia
+}
+
+// demuxerCacheState são os campos usados de demuxer-cache-state
+type demuxerCacheState struct {
+	CacheDuration float64 `json:"cache-duration"`
+	ForwardBytes  int64   `json:"fw-bytes"`
+}
+
+// observeProperties registra os observers no engine
+func (p *Player) observeProperties() {
+	for i, prop := range observedProperties {
+		if err := p.engine.ObserveProperty(uint64(i+1), prop.name, prop.format); err != nil {
+			fmt.Printf("⚠️ Não foi possível observar %s: %v\n", prop.name, err)
+		}
+	}
+}
+
+// Snapshot retorna uma cópia do estado atual da reprodução
+func (p *Player) Snapshot() PlaybackSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.snapshot
+	s.Tracks = append([]Track(nil), p.snapshot.Tracks...)
+	s.Playlist = append([]PlaylistEntry(nil), p.snapshot.Playlist...)
+	return s
+}
+
+// handlePropertyChange atualiza o snapshot com o valor que veio no evento
+// Os eventos são publicados depois de soltar o lock
+func (p *Player) handlePropertyChange(event *EngineEvent) {
+	prop := event.Property
+	if prop == nil {
+		return
+	}
+
+	var events []Event
+	warnDropped := false
+	skipPos := -1.0 // posição válida de time-pos para os trechos de pular
+	var tracks []Track
+
+	p.mu.Lock()
+	s := &p.snapshot
+
+	switch prop.Name {
+	case "time-pos":
+		pos := propDouble(prop.Data)
+		s.Position = pos
+		if prop.Data != nil {
+			skipPos = pos
+		}
+		if prop.Data != nil && pos > 0 {
+			// Guarda a última posição válida: no fim do arquivo time-pos fica indisponível
+			p.media.entry.Position = pos
+		}
+		if math.Abs(pos-p.lastTimeUpdate) >= timeUpdateStep || pos == 0 {
+			p.lastTimeUpdate = pos
+			events = append(events, TimeUpdateEvent{Position: pos, Duration: s.Duration})
+		}
+
+	case "duration":
+		s.Duration = propDouble(prop.Data)
+		events = append(events, TimeUpdateEvent{Position: s.Position, Duration: s.Duration})
+
+	case "pause":
+		s.Paused = propFlag(prop.Data)
+
+	case "track-list":
+		text, _ := prop.Data.(string)
+		var err error
+		tracks, err = parseTrackList(text)
+		if err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+			break
+		}
+		s.Tracks = tracks
+		events = append(events, TrackListChangedEvent{Tracks: append([]Track(nil), tracks...)})
+
+	case "chapter":
+		s.Chapter = -1
+		if prop.Data != nil {
+			s.Chapter = propInt64(prop.Data)
+		}
+
+	case "paused-for-cache":
+		buffering := propFlag(prop.Data)
+		if buffering != s.Buffering {
+			s.Buffering = buffering
+			events = append(events, BufferingEvent{Buffering: buffering})
+		}
+
+	case "demuxer-cache-state":
+		var cache demuxerCacheState
+		if text, ok := prop.Data.(string); ok && text != "" {
+			json.Unmarshal([]byte(text), &cache)
+		}
+		s.CacheDuration = cache.CacheDuration
+		s.CacheBytes = cache.ForwardBytes
+
+	case "eof-reached":
+		s.EOFReached = propFlag(prop.Data)
+
+	case "volume":
+		s.Volume = propDouble(prop.Data)
+		p.volume = int(math.Round(s.Volume))
+
+	case "frame-drop-count":
+		dropped := propInt64(prop.Data)
+		// Sem qualidade automática, apenas avisar (uma vez) sobre frames perdidos
+		warnDropped = dropped > droppedFramesWarning && s.DroppedFrames <= droppedFramesWarning &&
+			p.currentMode == ModeHigh && p.adaptive == nil
+		s.DroppedFrames = dropped
+
+	case "seeking":
+		s.Seeking = propFlag(prop.Data)
+
+	case "idle-active":
+		s.Idle = propFlag(prop.Data)
+
+	case "sub-delay":
+		// Só interessa ao perfil da série (abaixo)
+
+	case "playlist":
+		text, _ := prop.Data.(string)
+		entries, err := parsePlaylist(text)
+		if err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+			break
+		}
+		s.Playlist = entries
+		s.PlaylistPos = -1
+		for i, e := range entries {
+			if e.Current {
+				s.PlaylistPos = i
+			}
+		}
+		events = append(events, PlaylistChangedEvent{
+			Entries:  append([]PlaylistEntry(nil), entries...),
+			Position: s.PlaylistPos,
+		})
+	}
+
+	// pause, seeking, paused-for-cache, eof-reached e idle-active mudam o estado
+	if ev := p.refreshState(); ev != nil {
+		events = append(events, ev)
+	}
+	profileChange := p.profileChange(prop, tracks)
+	p.mu.Unlock()
+
+	if warnDropped {
+		fmt.Println("⚠️ Muitos frames perdidos! Considere baixar o modo de qualidade (ou ative -adaptive).")
+	}
+	for _, ev := range events {
+		p.emit(ev)
+	}
+	if skipPos >= 0 {
+		p.updateSkip(skipPos)
+	}
+	if profileChange != nil {
+		p.recordProfile(profileChange)
+	}
+}
+
+// propDouble converte o valor de um evento de propriedade em float64
+func propDouble(data interface{}) float64 {
+	switch v := data.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// propInt64 converte o valor de um evento de propriedade em int64
+func propInt64(data interface{}) int64 {
+	switch v := data.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// propFlag converte o valor de um evento de propriedade em bool
+// O go-mpv entrega flags como int (0/1); o FakeEngine aceita bool
+func propFlag(data interface{}) bool {
+	switch v := data.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	}
+	return false
+}
@@ -0,0 +1,483 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReleaseInfo são os dados extraídos do nome de um arquivo de release
+// Ex: "[SubsPlease] Sousou no Frieren - 05v2 (1080p) [F02B9CEE].mkv"
+type ReleaseInfo struct {
+	Series     string `json:"series"`
+	Season     int    `json:"season,omitempty"`  // 0 = não informada
+	Episode    int    `json:"episode,omitempty"` // 0 = não encontrado
+	Version    int    `json:"version,omitempty"` // v2 → 2 (0 = primeira versão)
+	Group      string `json:"group,omitempty"`
+	Resolution string `json:"resolution,omitempty"` // "1080p"
+	Source     string `json:"source,omitempty"`     // "WEB-DL", "BD", "HDTV"...
+	Year       int    `json:"year,omitempty"`
+	Language   string `json:"language,omitempty"` // sufixo de legenda (".eng.srt")
+	Extra      bool   `json:"extra,omitempty"`    // NCOP, NCED, PV, Menu... (fora da playlist)
+}
+
+// Extensões reconhecidas nos nomes de arquivo
+var (
+	videoExts    = map[string]bool{".mkv": true, ".mp4": true, ".avi": true, ".webm": true, ".m4v": true, ".mov": true, ".ts": true, ".m2ts": true, ".wmv": true, ".flv": true, ".ogm": true}
+	subtitleExts = map[string]bool{".srt": true, ".ass": true, ".ssa": true, ".vtt": true, ".sub": true, ".sup": true}
+)
+
+var (
+	reBracket    = regexp.MustCompile(`\[([^\]]*)\]|\(([^)]*)\)|【([^】]*)】`)
+	reResolution = regexp.MustCompile(`(?i)(?:^|[^0-9a-z]|bd|web|tv)(?:(\d{3,4})[pi]|\d{3,4}x(\d{3,4})|(4k|uhd))\b`)
+	reSource     = regexp.MustCompile(`(?i)\b(HDTV|WEB-?DL|WEB-?Rip|WEB|BD-?Rip|BDMV|BD|Blu-?Ray|DVD-?Rip|DVD|TV-?Rip)(?:\b|\d{3,4}[pi]\b)`)
+	reTech       = regexp.MustCompile(`(?i)\b(?:\d{3,4}[pi]|\d{3,4}x\d{3,4}|4k|uhd|hdtv|web-?dl|web-?rip|web|bd-?rip|bdmv|bd|blu-?ray|dvd-?rip|dvd|x26[45]|h\.?26[45]|hevc|avc|aac\d?(?:\.\d)?|flac|opus|ac3|e-?ac-?3|dts|10-?bits?|8-?bits?|hi10p?|dual[ .-]?audio|multi-?subs?|remux|batch|complete)\b`)
+	reCRC        = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`)
+	reYear       = regexp.MustCompile(`^(19|20)\d\d$`)
+	reSceneGroup = regexp.MustCompile(`-([A-Za-z0-9]+)$`)
+	reSpaces     = regexp.MustCompile(`\s+`)
+
+	// Padrões de episódio, do mais específico ao mais genérico
+	reSxE      = regexp.MustCompile(`(?i)\bS(\d{1,2})[\s._-]?E(\d{1,4})(?:-?E?\d{1,4})?(?:v(\d+))?\b`)
+	reNxN      = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})(?:v(\d+))?\b`)
+	reDash     = regexp.MustCompile(`(?i)\s[-–]\s(?:E|EP)?(\d{1,4})(?:-\d{1,4})?(?:v(\d+))?(?:\s|$)`)
+	reKeyword  = regexp.MustCompile(`(?i)\b(?:episode|episodio|episódio|session|ep|e)[\s.]?(\d{1,4})(?:v(\d+))?\b`)
+	reTrailing = regexp.MustCompile(`(?i)(?:^|\s)(\d{1,4})(?:v(\d+))?$`)
+
+	reVersion    = regexp.MustCompile(`(?i)\bv(\d)\b`)
+	reSeasonTail = regexp.MustCompile(`(?i)\s(?:S(\d{1,2})|Season\s?(\d{1,2})|Temporada\s?(\d{1,2})|(\d{1,2})(?:st|nd|rd|th)\s+Season)$`)
+	reSeasonWord = regexp.MustCompile(`(?i)(?:^|\s)(?:Season|Temporada)\s?(\d{1,2})$`)
+	reSeasonOnly = regexp.MustCompile(`(?i)(?:^|\s)(?:S|Season\s?|Temporada\s?)(\d{1,2})(?:\s|$)`)
+	reExtra      = regexp.MustCompile(`\b(?i:NC(?:OP|ED)\d*|PV\d*|Preview|Trailer|Menu\d*|CM\d*)\b|\b(?:OP|ED)\d*\b`)
+	reSubLang    = regexp.MustCompile(`\.([a-z]{2,3}(?:[-_][A-Za-z]{2})?)$`)
+)
+
+// ParseRelease extrai série, temporada, episódio, grupo e qualidade de um nome de arquivo
+func ParseRelease(filename string) ReleaseInfo {
+	name := filepath.Base(filename)
+	ext := strings.ToLower(filepath.Ext(name))
+	if videoExts[ext] || subtitleExts[ext] || ext == ".m3u8" || ext == ".m3u" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	var r ReleaseInfo
+	if subtitleExts[ext] {
+		if m := reSubLang.FindStringSubmatch(name); m != nil && !reTech.MatchString(m[1]) {
+			r.Language = m[1]
+			name = strings.TrimSuffix(name, "."+m[1])
+		}
+	}
+	parseReleaseName(name, &r)
+	return r
+}
+
+// ParseReleasePath é ParseRelease usando também as pastas (batches)
+// Ex: "[Group] Show (2023) [BD 1080p]/05.mkv" ou "Show/Season 2/Episode 05.mkv"
+func ParseReleasePath(path string) ReleaseInfo {
+	r := ParseRelease(path)
+
+	dir := filepath.Dir(path)
+	for i := 0; i < 2 && dir != "." && dir != filepath.Dir(dir); i++ {
+		var parent ReleaseInfo
+		parseReleaseName(filepath.Base(dir), &parent)
+		dir = filepath.Dir(dir)
+
+		if r.Season == 0 {
+			r.Season = parent.Season
+		}
+		if r.Group == "" {
+			r.Group = parent.Group
+		}
+		if r.Resolution == "" {
+			r.Resolution = parent.Resolution
+		}
+		if r.Source == "" {
+			r.Source = parent.Source
+		}
+		if r.Year == 0 {
+			r.Year = parent.Year
+		}
+		// Pasta "Season 2" não tem série: continua subindo
+		if r.Series == "" && parent.Series != "" {
+			r.Series = parent.Series
+		}
+		if r.Series != "" {
+			break
+		}
+	}
+	return r
+}
+
+// parseReleaseName faz o trabalho de ParseRelease em um nome sem extensão
+func parseReleaseName(name string, r *ReleaseInfo) {
+	name = strings.TrimSpace(name)
+
+	// 1. Tags entre colchetes/parênteses: grupo, resolução, fonte, ano, CRC...
+	var rest strings.Builder
+	last := 0
+	for _, m := range reBracket.FindAllStringSubmatchIndex(name, -1) {
+		rest.WriteString(name[last:m[0]])
+		rest.WriteByte(' ')
+		last = m[1]
+
+		content, square := "", false
+		switch {
+		case m[2] >= 0:
+			content, square = name[m[2]:m[3]], true
+		case m[4] >= 0:
+			content = name[m[4]:m[5]]
+		default:
+			content, square = name[m[6]:m[7]], true
+		}
+		leading := strings.TrimSpace(name[:m[0]]) == ""
+		content = strings.TrimSpace(strings.ReplaceAll(content, "_", " "))
+		classifyReleaseTag(content, square, leading, r)
+	}
+	rest.WriteString(name[last:])
+	text := strings.TrimSpace(rest.String())
+
+	// 2. Nomes no estilo scene (Show.S01E05.1080p.WEB-DL-GROUP) usam pontos como espaço
+	if !strings.Contains(reBracket.ReplaceAllString(name, ""), " ") {
+		last := text[strings.LastIndexAny(text, ". ")+1:]
+		if m := reSceneGroup.FindStringSubmatch(last); m != nil && r.Group == "" && !isTechToken(last) && !isTechToken(m[1]) {
+			r.Group = m[1]
+			text = strings.TrimSuffix(text, m[0])
+		}
+		text = strings.NewReplacer(".", " ", "_", " ").Replace(text)
+	} else {
+		text = strings.ReplaceAll(text, "_", " ")
+	}
+	text = strings.TrimSpace(reSpaces.ReplaceAllString(text, " "))
+
+	// 3. Resolução e fonte fora de colchetes
+	if r.Resolution == "" {
+		r.Resolution = resolutionOf(text)
+	}
+	if r.Source == "" {
+		r.Source = sourceOf(text)
+	}
+	r.Extra = r.Extra || reExtra.MatchString(text)
+
+	// 4. Tudo depois da primeira tag técnica é descartado
+	if loc := reTech.FindStringIndex(text); loc != nil && loc[0] > 0 {
+		text = strings.TrimSpace(text[:loc[0]])
+	}
+
+	// 5. Episódio (a série é o texto antes dele)
+	series := text
+	switch {
+	case matchEpisode(reSxE, text, r, &series, true):
+	case matchEpisode(reNxN, text, r, &series, true):
+	case matchEpisode(reDash, text, r, &series, false):
+	case matchEpisode(reKeyword, text, r, &series, false):
+	case reSeasonWord.MatchString(text):
+		// Pasta de temporada: "Season 2" não é o episódio 2
+		m := reSeasonWord.FindStringSubmatchIndex(text)
+		r.Season, _ = strconv.Atoi(text[m[2]:m[3]])
+		series = text[:m[0]]
+	case matchEpisode(reTrailing, text, r, &series, false):
+	default:
+		// Pasta de temporada ou batch sem episódio: "Show S01", "Season 2"
+		if m := reSeasonOnly.FindStringSubmatchIndex(text); m != nil {
+			r.Season, _ = strconv.Atoi(text[m[2]:m[3]])
+			series = text[:m[0]]
+		}
+	}
+
+	r.Series = cleanSeries(series, r)
+}
+
+// matchEpisode aplica um padrão de episódio; withSeason indica grupos (temporada, episódio)
+func matchEpisode(re *regexp.Regexp, text string, r *ReleaseInfo, series *string, withSeason bool) bool {
+	m := re.FindStringSubmatchIndex(text)
+	if m == nil {
+		return false
+	}
+
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return text[m[2*i]:m[2*i+1]]
+	}
+
+	epGroup, verGroup := 1, 2
+	if withSeason {
+		r.Season, _ = strconv.Atoi(group(1))
+		epGroup, verGroup = 2, 3
+	}
+
+	ep := group(epGroup)
+	// Ano solto no fim não é episódio ("Show 2023")
+	if !withSeason && len(ep) == 4 && reYear.MatchString(ep) {
+		return false
+	}
+	r.Episode, _ = strconv.Atoi(ep)
+	if v := group(verGroup); v != "" {
+		r.Version, _ = strconv.Atoi(v)
+	} else if v := reVersion.FindStringSubmatch(text[m[1]:]); v != nil {
+		r.Version, _ = strconv.Atoi(v[1])
+	}
+
+	*series = text[:m[0]]
+	return true
+}
+
+// classifyReleaseTag interpreta o conteúdo de [..] ou (..)
+func classifyReleaseTag(content string, square, leading bool, r *ReleaseInfo) {
+	lower := strings.ToLower(content)
+	switch {
+	case content == "":
+		return
+	case reYear.MatchString(content):
+		r.Year, _ = strconv.Atoi(content)
+		return
+	case reCRC.MatchString(content):
+		return
+	case strings.Contains(lower, "tvdbid") || strings.Contains(lower, "tmdbid") ||
+		strings.Contains(lower, "imdbid") || strings.Contains(lower, "anidb"):
+		return
+	}
+
+	technical := false
+	if res := resolutionOf(content); res != "" {
+		r.Resolution, technical = res, true
+	}
+	if src := sourceOf(content); src != "" {
+		r.Source, technical = src, true
+	}
+	if technical || reTech.MatchString(content) {
+		return
+	}
+	if reExtra.MatchString(content) {
+		r.Extra = true
+		return
+	}
+
+	// Primeiro [..] do nome é sempre o grupo; depois, o primeiro [..] desconhecido
+	if square && (leading || r.Group == "") {
+		r.Group = content
+	}
+}
+
+// isTechToken indica uma palavra inteira técnica ("WEB-DL", "x264", "1080p")
+func isTechToken(s string) bool {
+	loc := reTech.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && loc[1] == len(s)
+}
+
+// resolutionOf retorna "1080p", "720p"... (vazio se não houver)
+func resolutionOf(s string) string {
+	m := reResolution.FindStringSubmatch(s)
+	switch {
+	case m == nil:
+		return ""
+	case m[1] != "":
+		return m[1] + "p"
+	case m[2] != "":
+		return m[2] + "p"
+	}
+	return "2160p"
+}
+
+// sourceOf retorna a fonte padronizada ("WEB-DL", "BD"...; vazio se não houver)
+func sourceOf(s string) string {
+	m := reSource.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return normalizeSource(m[1])
+}
+
+// normalizeSource padroniza a grafia da fonte
+func normalizeSource(s string) string {
+	switch strings.ToLower(strings.ReplaceAll(s, "-", "")) {
+	case "webdl":
+		return "WEB-DL"
+	case "webrip":
+		return "WEBRip"
+	case "web":
+		return "WEB"
+	case "bd", "bdmv", "bluray":
+		return "BD"
+	case "bdrip":
+		return "BDRip"
+	case "dvd", "dvdrip":
+		return "DVD"
+	case "tvrip", "hdtv":
+		return "HDTV"
+	}
+	return s
+}
+
+// cleanSeries limpa o nome da série e tira dele temporada e ano
+func cleanSeries(series string, r *ReleaseInfo) string {
+	series = strings.Trim(series, " -–_.~:")
+
+	if m := reSeasonTail.FindStringSubmatch(series); m != nil {
+		for _, g := range m[1:] {
+			if g != "" {
+				if r.Season == 0 {
+					r.Season, _ = strconv.Atoi(g)
+				}
+				break
+			}
+		}
+		series = strings.Trim(series[:len(series)-len(m[0])], " -–_.~:")
+	}
+
+	if fields := strings.Fields(series); len(fields) > 1 {
+		if last := fields[len(fields)-1]; reYear.MatchString(last) {
+			if r.Year == 0 {
+				r.Year, _ = strconv.Atoi(last)
+			}
+			fields = fields[:len(fields)-1]
+		}
+		// Sonarr às vezes repete a série: "Gachiakuta.Gachiakuta.s01e23"
+		if n := len(fields); n%2 == 0 && strings.EqualFold(strings.Join(fields[:n/2], " "), strings.Join(fields[n/2:], " ")) {
+			fields = fields[:n/2]
+		}
+		series = strings.Join(fields, " ")
+	}
+	return series
+}
+
+// DisplayTitle retorna um título limpo: "Gachiakuta S01E23", "Sousou no Frieren - 05"
+func (r ReleaseInfo) DisplayTitle() string {
+	switch {
+	case r.Series == "":
+		return ""
+	case r.Episode == 0:
+		return r.Series
+	case r.Season > 0:
+		return fmt.Sprintf("%s S%02dE%02d", r.Series, r.Season, r.Episode)
+	}
+	return fmt.Sprintf("%s - %02d", r.Series, r.Episode)
+}
+
+// CleanTitle é o título mostrado na janela para um arquivo ou URL
+// Sem série reconhecida, usa o nome do arquivo sem extensão
+func CleanTitle(path string) string {
+	if strings.Contains(path, "://") {
+		path = stripURLTokens(path)
+	}
+	if title := ParseReleasePath(path).DisplayTitle(); title != "" {
+		return title
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// --- Ordenação de episódios ---
+
+// SortEpisodes ordena arquivos por série, temporada, episódio e versão
+// Arquivos sem episódio vão para o fim, em ordem de nome
+func SortEpisodes(paths []string) {
+	infos := make(map[string]ReleaseInfo, len(paths))
+	for _, p := range paths {
+		infos[p] = ParseReleasePath(p)
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return episodeLess(infos[paths[i]], infos[paths[j]], paths[i], paths[j])
+	})
+}
+
+// episodeLess compara dois arquivos para SortEpisodes
+func episodeLess(a, b ReleaseInfo, pathA, pathB string) bool {
+	if (a.Episode == 0) != (b.Episode == 0) {
+		return a.Episode != 0
+	}
+	if sa, sb := strings.ToLower(a.Series), strings.ToLower(b.Series); sa != sb {
+		return sa < sb
+	}
+	if a.Season != b.Season {
+		return a.Season < b.Season
+	}
+	if a.Episode != b.Episode {
+		return a.Episode < b.Episode
+	}
+	if a.Version != b.Version {
+		return a.Version < b.Version
+	}
+	return strings.ToLower(pathA) < strings.ToLower(pathB)
+}
+
+// EpisodePlaylist lista os vídeos de uma pasta em ordem de episódio
+// Extras (NCOP/NCED/PV...) ficam de fora e, com v1 e v2 do mesmo episódio, só a última versão entra
+func EpisodePlaylist(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type episodeKey struct {
+		series          string
+		season, episode int
+	}
+	newest := make(map[episodeKey]string)
+	versions := make(map[string]int)
+	var files []string
+
+	for _, e := range entries {
+		if e.IsDir() || !videoExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		info := ParseReleasePath(path)
+		if info.Extra {
+			continue
+		}
+		if info.Episode == 0 {
+			files = append(files, path)
+			continue
+		}
+
+		key := episodeKey{strings.ToLower(info.Series), info.Season, info.Episode}
+		if prev, ok := newest[key]; ok && versions[prev] >= info.Version {
+			continue
+		}
+		newest[key] = path
+		versions[path] = info.Version
+	}
+
+	for _, path := range newest {
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nenhum vídeo em %s", dir)
+	}
+	SortEpisodes(files)
+	return files, nil
+}
+
+// --- Integração com o Player ---
+
+// SetAutoTitle faz o título da janela acompanhar o episódio aberto
+// format recebe o título limpo em %s (ex: "▶ %s - GoAnime Player"); vazio desliga
+func (p *Player) SetAutoTitle(format string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.titleFormat = format
+}
+
+// applyAutoTitle atualiza o título da janela quando um arquivo termina de abrir
+// O título do .m3u (#EXTINF), se houver, vence o do nome do arquivo
+func (p *Player) applyAutoTitle(path string) {
+	p.mu.Lock()
+	format := p.titleFormat
+	p.mu.Unlock()
+
+	if format == "" || path == "" {
+		return
+	}
+	title, ok := p.entryTitle(path)
+	if !ok {
+		title = CleanTitle(path)
+	}
+	p.SetWindowTitle(fmt.Sprintf(format, title))
+}
@@ -0,0 +1,268 @@
+package player
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		want ReleaseInfo
+	}{
+		// Sonarr/scene
+		{
+			"Gachiakuta.Gachiakuta.(2025).[tvdbid-450537].s01e23.[HDTV-720p].[SubsPlease].eng.srt",
+			ReleaseInfo{Series: "Gachiakuta", Season: 1, Episode: 23, Group: "SubsPlease", Resolution: "720p", Source: "HDTV", Year: 2025, Language: "eng"},
+		},
+		{
+			"Spy.x.Family.S02E10.1080p.WEB-DL.x264-GROUP.mkv",
+			ReleaseInfo{Series: "Spy x Family", Season: 2, Episode: 10, Group: "GROUP", Resolution: "1080p", Source: "WEB-DL"},
+		},
+		{
+			"Re.Zero.kara.Hajimeru.Isekai.Seikatsu.S03E01.1080p.CR.WEB-DL.AAC2.0.H.264-VARYG.mkv",
+			ReleaseInfo{Series: "Re Zero kara Hajimeru Isekai Seikatsu", Season: 3, Episode: 1, Group: "VARYG", Resolution: "1080p", Source: "WEB-DL"},
+		},
+		{
+			"One.Piece.E1085.1080p.WEB.H264-SubsPlease.mkv",
+			ReleaseInfo{Series: "One Piece", Episode: 1085, Group: "SubsPlease", Resolution: "1080p", Source: "WEB"},
+		},
+		{
+			"My.Hero.Academia.S07E01.mkv",
+			ReleaseInfo{Series: "My Hero Academia", Season: 7, Episode: 1},
+		},
+		{
+			"Jujutsu.Kaisen.S02E14.pt-BR.srt",
+			ReleaseInfo{Series: "Jujutsu Kaisen", Season: 2, Episode: 14, Language: "pt-BR"},
+		},
+		{
+			"Vinland Saga - S02E24 - Home.mkv",
+			ReleaseInfo{Series: "Vinland Saga", Season: 2, Episode: 24},
+		},
+		{
+			"Frieren Beyond Journey's End S01E28 Sub.mkv",
+			ReleaseInfo{Series: "Frieren Beyond Journey's End", Season: 1, Episode: 28},
+		},
+		{
+			"Attack on Titan 3x05.mkv",
+			ReleaseInfo{Series: "Attack on Titan", Season: 3, Episode: 5},
+		},
+		{
+			"Show.S01E05E06.720p.HDTV.mkv",
+			ReleaseInfo{Series: "Show", Season: 1, Episode: 5, Resolution: "720p", Source: "HDTV"},
+		},
+
+		// Fansub: [Grupo] Série - 05 (1080p) [CRC]
+		{
+			"[SubsPlease] Sousou no Frieren - 05 (1080p) [F02B9CEE].mkv",
+			ReleaseInfo{Series: "Sousou no Frieren", Episode: 5, Group: "SubsPlease", Resolution: "1080p"},
+		},
+		{
+			"[SubsPlease] Sousou no Frieren - 05v2 (1080p) [F02B9CEE].mkv",
+			ReleaseInfo{Series: "Sousou no Frieren", Episode: 5, Version: 2, Group: "SubsPlease", Resolution: "1080p"},
+		},
+		{
+			"[SubsPlease] Dandadan - 12 (1080p) [ABCD1234].mkv",
+			ReleaseInfo{Series: "Dandadan", Episode: 12, Group: "SubsPlease", Resolution: "1080p"},
+		},
+		{
+			"[Erai-raws] Kimetsu no Yaiba - Hashira Geiko-hen - 03 [1080p][Multiple Subtitle][ABCDEF12].mkv",
+			ReleaseInfo{Series: "Kimetsu no Yaiba - Hashira Geiko-hen", Episode: 3, Group: "Erai-raws", Resolution: "1080p"},
+		},
+		{
+			"[Erai-raws] Re Zero kara Hajimeru Isekai Seikatsu 3rd Season - 08 [1080p][HEVC].mkv",
+			ReleaseInfo{Series: "Re Zero kara Hajimeru Isekai Seikatsu", Season: 3, Episode: 8, Group: "Erai-raws", Resolution: "1080p"},
+		},
+		{
+			"[Judas] Jujutsu Kaisen - S02E14.mkv",
+			ReleaseInfo{Series: "Jujutsu Kaisen", Season: 2, Episode: 14, Group: "Judas"},
+		},
+		{
+			"[EMBER] Oshi no Ko S2 - 05.mkv",
+			ReleaseInfo{Series: "Oshi no Ko", Season: 2, Episode: 5, Group: "EMBER"},
+		},
+		{
+			"[ASW] Mushoku Tensei S2 - 01 [1080p HEVC][A1B2C3D4].mkv",
+			ReleaseInfo{Series: "Mushoku Tensei", Season: 2, Episode: 1, Group: "ASW", Resolution: "1080p"},
+		},
+		{
+			"[HorribleSubs] One Punch Man S2 - 12 [720p].mkv",
+			ReleaseInfo{Series: "One Punch Man", Season: 2, Episode: 12, Group: "HorribleSubs", Resolution: "720p"},
+		},
+		{
+			"[Commie] Steins;Gate - 01 [BD 720p AAC] [5D31F4C4].mkv",
+			ReleaseInfo{Series: "Steins;Gate", Episode: 1, Group: "Commie", Resolution: "720p", Source: "BD"},
+		},
+		{
+			"[Beatrice-Raws] Made in Abyss 01 [BDRip 1920x1080 HEVC FLAC].mkv",
+			ReleaseInfo{Series: "Made in Abyss", Episode: 1, Group: "Beatrice-Raws", Resolution: "1080p", Source: "BDRip"},
+		},
+		{
+			"[DB]Haikyuu!! To the Top_-_01_(Dual Audio_10bit_BD1080p_x265).mkv",
+			ReleaseInfo{Series: "Haikyuu!! To the Top", Episode: 1, Group: "DB", Resolution: "1080p", Source: "BD"},
+		},
+		{
+			"[Trix] Chainsaw Man - 01 (AV1 1080p) [Multi Subs].mkv",
+			ReleaseInfo{Series: "Chainsaw Man", Episode: 1, Group: "Trix", Resolution: "1080p"},
+		},
+		{
+			"[Anime Time] Hunter x Hunter (2011) - 148 [1080p][HEVC 10bit x265][AAC][Multi Sub].mkv",
+			ReleaseInfo{Series: "Hunter x Hunter", Episode: 148, Group: "Anime Time", Resolution: "1080p", Year: 2011},
+		},
+		{
+			"[SubsPlease] Tokyo Revengers (2023) - 01 (720p).mkv",
+			ReleaseInfo{Series: "Tokyo Revengers", Episode: 1, Group: "SubsPlease", Resolution: "720p", Year: 2023},
+		},
+		{
+			"[Group] Series - 01-02 [1080p].mkv",
+			ReleaseInfo{Series: "Series", Episode: 1, Group: "Group", Resolution: "1080p"},
+		},
+		{
+			"[Group] Show Name - NCOP1 [1080p].mkv",
+			ReleaseInfo{Series: "Show Name - NCOP1", Group: "Group", Resolution: "1080p", Extra: true},
+		},
+
+		// Nomes "à mão"
+		{"Naruto Shippuden - 500.mkv", ReleaseInfo{Series: "Naruto Shippuden", Episode: 500}},
+		{"Cowboy Bebop - Session 05.mkv", ReleaseInfo{Series: "Cowboy Bebop", Episode: 5}},
+		{"Mob Psycho 100 III - 01.mkv", ReleaseInfo{Series: "Mob Psycho 100 III", Episode: 1}},
+		{"Bocchi the Rock! - 07 [1080p].mkv", ReleaseInfo{Series: "Bocchi the Rock!", Episode: 7, Resolution: "1080p"}},
+		{"Kaguya-sama wa Kokurasetai S3 - 13 END.mkv", ReleaseInfo{Series: "Kaguya-sama wa Kokurasetai", Season: 3, Episode: 13}},
+		{"Show Name - 2nd Season - 03.mkv", ReleaseInfo{Series: "Show Name", Season: 2, Episode: 3}},
+		{"Dr. Stone - 05.mkv", ReleaseInfo{Series: "Dr. Stone", Episode: 5}},
+		{"Show - Episódio 7 [720p].mp4", ReleaseInfo{Series: "Show", Episode: 7, Resolution: "720p"}},
+		{"Show_EP07_1080p.mkv", ReleaseInfo{Series: "Show", Episode: 7, Resolution: "1080p"}},
+		{"Anime 2023.mkv", ReleaseInfo{Series: "Anime", Year: 2023}},
+		{"05.mkv", ReleaseInfo{Episode: 5}},
+		{"Episode 05.mkv", ReleaseInfo{Episode: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRelease(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRelease(%q)\n got: %+v\nwant: %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReleasePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want ReleaseInfo
+	}{
+		{
+			filepath.Join("anime", "[Group] Show (2023) [BD 1080p]", "05.mkv"),
+			ReleaseInfo{Series: "Show", Episode: 5, Group: "Group", Resolution: "1080p", Source: "BD", Year: 2023},
+		},
+		{
+			filepath.Join("anime", "Show Name", "Season 2", "Episode 05.mkv"),
+			ReleaseInfo{Series: "Show Name", Season: 2, Episode: 5},
+		},
+		{
+			filepath.Join("anime", "Show.S01.1080p.BluRay.x264-GRP", "Show.S01E03.mkv"),
+			ReleaseInfo{Series: "Show", Season: 1, Episode: 3, Group: "GRP", Resolution: "1080p", Source: "BD"},
+		},
+		{
+			// O nome do arquivo vence a pasta
+			filepath.Join("Downloads", "[SubsPlease] Dandadan - 12 (1080p).mkv"),
+			ReleaseInfo{Series: "Dandadan", Episode: 12, Group: "SubsPlease", Resolution: "1080p"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ParseReleasePath(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReleasePath(%q)\n got: %+v\nwant: %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Gachiakuta.Gachiakuta.(2025).[tvdbid-450537].s01e23.[HDTV-720p].[SubsPlease].mkv", "Gachiakuta S01E23"},
+		{"[SubsPlease] Sousou no Frieren - 05 (1080p) [F02B9CEE].mkv", "Sousou no Frieren - 05"},
+		{"Naruto Shippuden - 500.mkv", "Naruto Shippuden - 500"},
+		{filepath.Join("[Group] Show (2023) [BD 1080p]", "05.mkv"), "Show - 05"},
+		{"https://cdn.example.com/anime/Show.S01E02.mkv?token=abc#t=10", "Show S01E02"},
+		{"https://cdn.example.com/stream/master.m3u8?token=abc", "master"},
+		{"05.mkv", "05"},
+		{"Movie.mkv", "Movie"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := CleanTitle(tt.path); got != tt.want {
+				t.Errorf("CleanTitle(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortEpisodes(t *testing.T) {
+	paths := []string{
+		"[SubsPlease] Show - 10 (1080p).mkv",
+		"Extras.mkv",
+		"[SubsPlease] Show - 2 (1080p).mkv",
+		"[SubsPlease] Show S2 - 01 (1080p).mkv",
+		"[SubsPlease] Show - 01 (1080p).mkv",
+		"[SubsPlease] Show - 02v2 (1080p).mkv",
+	}
+	want := []string{
+		"[SubsPlease] Show - 01 (1080p).mkv",
+		"[SubsPlease] Show - 2 (1080p).mkv",
+		"[SubsPlease] Show - 02v2 (1080p).mkv",
+		"[SubsPlease] Show - 10 (1080p).mkv",
+		"[SubsPlease] Show S2 - 01 (1080p).mkv",
+		"Extras.mkv",
+	}
+
+	SortEpisodes(paths)
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("SortEpisodes\n got: %q\nwant: %q", paths, want)
+	}
+}
+
+func TestEpisodePlaylist(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"[Group] Show - 03 [1080p].mkv",
+		"[Group] Show - 01 [1080p].mkv",
+		"[Group] Show - 02 [1080p].mkv",
+		"[Group] Show - 02v2 [1080p].mkv",
+		"[Group] Show - NCOP1 [1080p].mkv",
+		"[Group] Show - 01 [1080p].ass",
+		"notes.txt",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "Extras"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := EpisodePlaylist(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "[Group] Show - 01 [1080p].mkv"),
+		filepath.Join(dir, "[Group] Show - 02v2 [1080p].mkv"),
+		filepath.Join(dir, "[Group] Show - 03 [1080p].mkv"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EpisodePlaylist\n got: %q\nwant: %q", got, want)
+	}
+
+	if _, err := EpisodePlaylist(filepath.Join(dir, "Extras")); err == nil {
+		t.Error("EpisodePlaylist de pasta sem vídeos deveria falhar")
+	}
+}
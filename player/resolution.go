@@ -0,0 +1,154 @@
+package player
+
+import (
+	"fmt"
+)
+
+// UpscaleRule escolhe uma cadeia de shaders pelo fator de escala (tela / vídeo)
+// A primeira regra em que MinScale <= fator < MaxScale é usada (MaxScale 0 = sem limite)
+type UpscaleRule struct {
+	Label    string   `json:"label"` // ex: "none", "x2", "x4"
+	MinScale float64  `json:"minScale"`
+	MaxScale float64  `json:"maxScale"`
+	Shaders  []string `json:"shaders"`
+}
+
+// matches indica se o fator de escala está dentro da regra
+func (r UpscaleRule) matches(scale float64) bool {
+	return scale >= r.MinScale && (r.MaxScale == 0 || scale < r.MaxScale)
+}
+
+// ChainDecision explica qual cadeia de shaders foi escolhida para o arquivo
+type ChainDecision struct {
+	Mode          PerformanceMode `json:"mode"`
+	Rule          string          `json:"rule"`
+	Shaders       []string        `json:"shaders"`
+	SourceWidth   int64           `json:"sourceWidth"`
+	SourceHeight  int64           `json:"sourceHeight"`
+	DisplayWidth  int64           `json:"displayWidth"`
+	DisplayHeight int64           `json:"displayHeight"`
+	Scale         float64         `json:"scale"`
+	Reason        string          `json:"reason"`
+}
+
+// ModeChange descreve uma troca de modo ou de cadeia (veja ModeChangedEvent)
+type ModeChange struct {
+	Mode     PerformanceMode   `json:"mode"`
+	Reason   string            `json:"reason"`
+	Decision *ChainDecision    `json:"decision,omitempty"` // preenchido quando a cadeia foi escolhida pela resolução
+	Adaptive *AdaptiveDecision `json:"adaptive,omitempty"` // preenchido quando a qualidade automática trocou o modo
+}
+
+// selectUpscaleRule escolhe a regra para um vídeo de srcW x srcH numa tela dispW x dispH
+// O fator usado é o menor entre largura e altura (o vídeo precisa caber na tela)
+func selectUpscaleRule(rules []UpscaleRule, srcW, srcH, dispW, dispH int64) (UpscaleRule, float64, bool) {
+	if srcW <= 0 || srcH <= 0 || dispW <= 0 || dispH <= 0 {
+		return UpscaleRule{}, 0, false
+	}
+
+	scale := float64(dispW) / float64(srcW)
+	if s := float64(dispH) / float64(srcH); s < scale {
+		scale = s
+	}
+
+	for _, r := range rules {
+		if r.matches(scale) {
+			return r, scale, true
+		}
+	}
+	return UpscaleRule{}, scale, false
+}
+
+// getInt64 lê uma propriedade inteira do engine (0 se indisponível)
+func (p *Player) getInt64(name string) int64 {
+	val, err := p.engine.GetProperty(name, FormatInt64)
+	if err != nil {
+		return 0
+	}
+	if n, ok := val.(int64); ok {
+		return n
+	}
+	return 0
+}
+
+// getFloat64 lê uma propriedade numérica do engine (0 se indisponível)
+func (p *Player) getFloat64(name string) float64 {
+	val, err := p.engine.GetProperty(name, FormatDouble)
+	if err != nil {
+		return 0
+	}
+	if n, ok := val.(float64); ok {
+		return n
+	}
+	return 0
+}
+
+// applyResolutionChain escolhe a cadeia de upscaling depois que o arquivo carrega
+// Usa as regras "upscale" do preset atual; presets sem regras mantêm a cadeia fixa
+func (p *Player) applyResolutionChain() *ChainDecision {
+	p.mu.Lock()
+
+	if p.animePreset != "" {
+		// Os shaders Anime4K já se desligam sozinhos quando não há upscale
+		p.mu.Unlock()
+		return nil
+	}
+
+	preset, ok := GetPreset(p.currentMode)
+	if !ok || len(preset.Upscale) == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+
+	d := &ChainDecision{
+		Mode:          p.currentMode,
+		SourceWidth:   p.getInt64("width"),
+		SourceHeight:  p.getInt64("height"),
+		DisplayWidth:  p.getInt64("display-width"),
+		DisplayHeight: p.getInt64("display-height"),
+	}
+
+	// Sem informação do monitor, usa o tamanho da janela
+	if d.DisplayWidth <= 0 || d.DisplayHeight <= 0 {
+		d.DisplayWidth = p.getInt64("osd-width")
+		d.DisplayHeight = p.getInt64("osd-height")
+	}
+
+	rule, scale, found := selectUpscaleRule(preset.Upscale, d.SourceWidth, d.SourceHeight, d.DisplayWidth, d.DisplayHeight)
+	d.Scale = scale
+
+	switch {
+	case scale == 0:
+		d.Rule = "default"
+		d.Shaders = preset.Shaders
+		d.Reason = "resolução do vídeo ou da tela indisponível, usando cadeia padrão do preset"
+	case !found:
+		d.Rule = "default"
+		d.Shaders = preset.Shaders
+		d.Reason = fmt.Sprintf("nenhuma regra para escala %.2fx, usando cadeia padrão do preset", scale)
+	default:
+		d.Rule = rule.Label
+		d.Shaders = rule.Shaders
+		d.Reason = fmt.Sprintf("%dx%d → %dx%d (escala %.2fx): regra %q",
+			d.SourceWidth, d.SourceHeight, d.DisplayWidth, d.DisplayHeight, scale, rule.Label)
+	}
+
+	p.engine.SetPropertyString("glsl-shaders", "")
+	p.appendShaders(d.Shaders)
+	p.lastDecision = d
+
+	p.mu.Unlock()
+
+	fmt.Printf("🔎 Cadeia de shaders: %s\n", d.Reason)
+
+	p.emit(ModeChangedEvent{ModeChange{Mode: d.Mode, Reason: d.Reason, Decision: d}})
+	return d
+}
+
+// GetChainDecision retorna a última decisão de cadeia por resolução (nil se nenhuma)
+func (p *Player) GetChainDecision() *ChainDecision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lastDecision
+}
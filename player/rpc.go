@@ -0,0 +1,434 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Códigos de erro do JSON-RPC 2.0
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcServerError    = -32000 // o método do player retornou erro
+)
+
+// rpcQueueSize é quantas mensagens podem ficar pendentes por conexão
+// Notificações são descartadas se o cliente não acompanhar; respostas nunca
+const rpcQueueSize = 256
+
+// rpcRequest é uma requisição (ou notificação, sem id) recebida do cliente
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// rpcResponse é a resposta enviada ao cliente
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcNotification é um evento enviado do player para o cliente
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// RPCError é o objeto de erro do JSON-RPC
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc %d: %s", e.Code, e.Message)
+}
+
+// RPCServer expõe os métodos do WailsPlayer via JSON-RPC 2.0 num socket local
+// Cada mensagem é um objeto JSON (ou lote) por linha; parâmetros são posicionais
+type RPCServer struct {
+	player  *WailsPlayer
+	target  reflect.Value
+	methods map[string]reflect.Method
+
+	cancel context.CancelFunc // encerra a assinatura de eventos
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[*rpcConn]struct{}
+	closed   bool
+}
+
+// rpcConn é uma conexão de cliente com sua fila de escrita
+type rpcConn struct {
+	conn    net.Conn
+	out     chan []byte
+	done    chan struct{}
+	once    sync.Once
+	dropped int
+}
+
+// NewRPCServer cria o servidor e repassa os eventos do player como notificações
+// O nome da notificação é o EventType (timeUpdate, stateChanged, error...)
+func NewRPCServer(w *WailsPlayer) *RPCServer {
+	s := &RPCServer{
+		player:  w,
+		target:  reflect.ValueOf(w),
+		methods: make(map[string]reflect.Method),
+		conns:   make(map[*rpcConn]struct{}),
+	}
+
+	t := s.target.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if rpcCallable(m.Type) {
+			s.methods[m.Name] = m
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	events := w.Subscribe(ctx)
+	go func() {
+		for ev := range events {
+			s.Notify(string(ev.Type()), ev)
+		}
+	}()
+
+	return s
+}
+
+// rpcCallable indica se um método pode ser chamado com parâmetros JSON
+// Métodos que recebem funções, canais ou contextos (OnEvent, Subscribe) ficam de fora
+func rpcCallable(t reflect.Type) bool {
+	for i := 1; i < t.NumIn(); i++ {
+		switch t.In(i).Kind() {
+		case reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer:
+			return false
+		}
+	}
+	return true
+}
+
+// Methods retorna os nomes dos métodos expostos, em ordem alfabética
+func (s *RPCServer) Methods() []string {
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListenAndServe abre o socket de controle no caminho e atende até Close
+func (s *RPCServer) ListenAndServe(path string) error {
+	l, err := ListenRPC(path)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// ListenRPC abre o socket Unix do servidor de controle
+// No Windows 10+ o mesmo socket AF_UNIX é usado (arquivo no caminho informado)
+func ListenRPC(path string) (net.Listener, error) {
+	// Socket antigo de uma instância que caiu: remove; instância viva: erro
+	if _, err := os.Stat(path); err == nil {
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+			return nil, fmt.Errorf("já existe um player atendendo em %s", path)
+		}
+		os.Remove(path)
+	}
+
+	l, err := listenUnix(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir socket de controle: %w", err)
+	}
+	// Só o dono controla o player; sem a permissão, melhor não abrir o socket
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("erro ao proteger socket de controle: %w", err)
+	}
+	return l, nil
+}
+
+// Serve atende conexões do listener até Close
+func (s *RPCServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return net.ErrClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		c := &rpcConn{conn: conn, out: make(chan []byte, rpcQueueSize), done: make(chan struct{})}
+		if !s.addConn(c) {
+			conn.Close()
+			return nil
+		}
+		go s.writeLoop(c)
+		go s.readLoop(c)
+	}
+}
+
+// Close fecha o listener e todas as conexões
+func (s *RPCServer) Close() error {
+	s.cancel()
+
+	s.mu.Lock()
+	s.closed = true
+	l := s.listener
+	conns := s.conns
+	s.conns = make(map[*rpcConn]struct{})
+	s.mu.Unlock()
+
+	for c := range conns {
+		c.close()
+	}
+	if l != nil {
+		return l.Close()
+	}
+	return nil
+}
+
+// Notify envia uma notificação para todos os clientes conectados
+// Nunca bloqueia: clientes com a fila cheia perdem a notificação
+func (s *RPCServer) Notify(method string, params interface{}) {
+	data, err := json.Marshal(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.conns {
+		select {
+		case c.out <- data:
+		default:
+			c.dropped++
+		}
+	}
+}
+
+func (s *RPCServer) addConn(c *rpcConn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	return true
+}
+
+func (s *RPCServer) removeConn(c *rpcConn) {
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	c.close()
+}
+
+func (c *rpcConn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// send enfileira uma resposta (bloqueia até haver espaço ou a conexão fechar)
+func (c *rpcConn) send(data []byte) {
+	select {
+	case c.out <- data:
+	case <-c.done:
+	}
+}
+
+func (s *RPCServer) writeLoop(c *rpcConn) {
+	for {
+		select {
+		case data := <-c.out:
+			if _, err := c.conn.Write(append(data, '\n')); err != nil {
+				s.removeConn(c)
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (s *RPCServer) readLoop(c *rpcConn) {
+	defer s.removeConn(c)
+
+	dec := json.NewDecoder(c.conn)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				// Escreve direto: a conexão fecha logo em seguida (não há como ressincronizar)
+				data := mustMarshal(rpcResponse{JSONRPC: "2.0", Error: &RPCError{rpcParseError, err.Error()}, ID: json.RawMessage("null")})
+				c.conn.Write(append(data, '\n'))
+			}
+			return
+		}
+
+		if out := s.handleMessage(raw); out != nil {
+			c.send(out)
+		}
+	}
+}
+
+// handleMessage processa uma requisição ou um lote; nil quando não há resposta
+func (s *RPCServer) handleMessage(raw json.RawMessage) []byte {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		resp := s.handleRequest(raw)
+		if resp == nil {
+			return nil
+		}
+		return mustMarshal(resp)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+		return mustMarshal(rpcResponse{JSONRPC: "2.0", Error: &RPCError{rpcInvalidRequest, "lote inválido"}, ID: json.RawMessage("null")})
+	}
+
+	var responses []*rpcResponse
+	for _, item := range batch {
+		if resp := s.handleRequest(item); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return mustMarshal(responses)
+}
+
+// handleRequest executa uma requisição; nil para notificações (sem id)
+func (s *RPCServer) handleRequest(raw json.RawMessage) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return &rpcResponse{JSONRPC: "2.0", Error: &RPCError{rpcInvalidRequest, "requisição JSON-RPC 2.0 inválida"}, ID: json.RawMessage("null")}
+	}
+
+	result, rpcErr := s.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+
+	resp := &rpcResponse{JSONRPC: "2.0", Error: rpcErr, ID: req.ID}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &RPCError{rpcInternalError, err.Error()}
+		} else {
+			resp.Result = data
+		}
+	}
+	return resp
+}
+
+// call decodifica os parâmetros e chama o método do WailsPlayer por reflexão
+func (s *RPCServer) call(name string, params json.RawMessage) (result interface{}, rpcErr *RPCError) {
+	switch name {
+	case "rpc.methods":
+		return s.Methods(), nil
+	}
+
+	m, ok := s.methods[name]
+	if !ok {
+		return nil, &RPCError{rpcMethodNotFound, "método desconhecido: " + name}
+	}
+
+	var args []json.RawMessage
+	if len(params) > 0 && string(params) != "null" {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, &RPCError{rpcInvalidParams, "params deve ser um array (parâmetros posicionais)"}
+		}
+	}
+
+	mt := m.Type
+	if len(args) != mt.NumIn()-1 {
+		return nil, &RPCError{rpcInvalidParams, fmt.Sprintf("%s espera %d parâmetro(s), recebeu %d", name, mt.NumIn()-1, len(args))}
+	}
+
+	in := []reflect.Value{s.target}
+	for i, arg := range args {
+		v := reflect.New(mt.In(i + 1))
+		if err := json.Unmarshal(arg, v.Interface()); err != nil {
+			return nil, &RPCError{rpcInvalidParams, fmt.Sprintf("parâmetro %d: %v", i+1, err)}
+		}
+		in = append(in, v.Elem())
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, rpcErr = nil, &RPCError{rpcInternalError, fmt.Sprint(r)}
+		}
+	}()
+
+	out := m.Func.Call(in)
+
+	// Último retorno do tipo error vira erro do RPC
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	if n := len(out); n > 0 && mt.Out(n-1) == errType {
+		if err, _ := out[n-1].Interface().(error); err != nil {
+			return nil, &RPCError{rpcServerError, err.Error()}
+		}
+		out = out[:n-1]
+	}
+
+	switch len(out) {
+	case 0:
+		return nil, nil
+	case 1:
+		return out[0].Interface(), nil
+	default:
+		list := make([]interface{}, len(out))
+		for i, v := range out {
+			list[i] = v.Interface()
+		}
+		return list, nil
+	}
+}
+
+// mustMarshal serializa uma resposta (resultados já vêm serializados)
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
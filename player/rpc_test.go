@@ -0,0 +1,35 @@
+package player
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestListenRPC(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissão Unix do socket não se aplica no Windows")
+	}
+	path := filepath.Join(t.TempDir(), "rpc.sock")
+
+	l, err := ListenRPC(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("permissão do socket = %o, want 600", perm)
+	}
+
+	// Instância viva no mesmo caminho: não remove o socket dela
+	if l2, err := ListenRPC(path); err == nil {
+		l2.Close()
+		t.Error("ListenRPC com outro player atendendo deveria falhar")
+	}
+}
@@ -0,0 +1,130 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ponte com o goanime.lua, feita com script-message do MPV:
+//
+//	Go → Lua: script-message-to goanime <comando> <argumentos...>
+//	  goanime-toast <texto> <segundos>
+//	  goanime-skip-button <tipo> <rótulo> <tecla> <fim em segundos>
+//	  goanime-skip-hide
+//	  goanime-mode-banner <modo> <nome> <ícone> <motivo>
+//
+//	Lua → Go: script-message player4k <pedido> <argumentos...>
+//	  switch-mode <modo> | mark-watched | next-episode | skip-segment
+//
+// Os pedidos chegam ao Go como client-message e viram ScriptRequestEvent
+
+// luaScriptName é o nome do cliente do goanime.lua no MPV (nome do arquivo sem .lua)
+const luaScriptName = "goanime"
+
+// scriptRequestTarget é o primeiro argumento dos script-message endereçados ao player
+const scriptRequestTarget = "player4k"
+
+// ScriptRequest é um pedido feito pelo goanime.lua
+type ScriptRequest string
+
+const (
+	ScriptSwitchMode  ScriptRequest = "switch-mode"
+	ScriptMarkWatched ScriptRequest = "mark-watched"
+	ScriptNextEpisode ScriptRequest = "next-episode"
+	ScriptSkipSegment ScriptRequest = "skip-segment"
+)
+
+// parseScriptRequest decodifica os argumentos de um client-message
+// ok é false para mensagens de outros scripts (ex: uosc)
+func parseScriptRequest(args []string) (req ScriptRequestEvent, ok bool, err error) {
+	if len(args) == 0 || args[0] != scriptRequestTarget {
+		return req, false, nil
+	}
+	if len(args) < 2 {
+		return req, true, fmt.Errorf("script-message %s sem pedido", scriptRequestTarget)
+	}
+
+	req.Request = ScriptRequest(args[1])
+	switch req.Request {
+	case ScriptSwitchMode:
+		if len(args) < 3 {
+			return req, true, fmt.Errorf("%s sem modo", req.Request)
+		}
+		mode, found := ParsePerformanceMode(args[2])
+		if !found {
+			return req, true, fmt.Errorf("modo desconhecido: %s", args[2])
+		}
+		req.Mode = mode
+	case ScriptMarkWatched, ScriptNextEpisode, ScriptSkipSegment:
+	default:
+		return req, true, fmt.Errorf("pedido desconhecido do script: %s", args[1])
+	}
+	return req, true, nil
+}
+
+// handleClientMessage atende um pedido do goanime.lua e publica ScriptRequestEvent
+func (p *Player) handleClientMessage(args []string) {
+	req, ok, err := parseScriptRequest(args)
+	if !ok {
+		return
+	}
+	if err != nil {
+		fmt.Printf("⚠️ goanime.lua: %v\n", err)
+		return
+	}
+
+	fmt.Printf("🎌 goanime.lua: %s\n", strings.Join(args[1:], " "))
+	switch req.Request {
+	case ScriptSwitchMode:
+		err = p.SetPerformanceMode(req.Mode)
+	case ScriptMarkWatched:
+		if err = p.MarkWatched(); err == nil {
+			p.ShowToast("✓ Marcado como assistido", 2*time.Second)
+		}
+	case ScriptNextEpisode:
+		err = p.Next()
+	case ScriptSkipSegment:
+		err = p.SkipCurrentSegment()
+	}
+
+	if err != nil {
+		req.Error = err.Error()
+		fmt.Printf("⚠️ goanime.lua: %s: %v\n", req.Request, err)
+		p.ShowToast("⚠️ "+err.Error(), 3*time.Second)
+	}
+	p.emit(req)
+}
+
+// sendScript envia um comando ao goanime.lua
+// Falha se o script não estiver carregado (ex: sem pacote de configuração)
+func (p *Player) sendScript(command string, args ...string) error {
+	return p.engine.Command(append([]string{"script-message-to", luaScriptName, command}, args...))
+}
+
+// ShowToast mostra uma notificação do goanime.lua (ou o OSD comum sem o script)
+func (p *Player) ShowToast(text string, d time.Duration) error {
+	secs := strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+	if err := p.sendScript("goanime-toast", text, secs); err == nil {
+		return nil
+	}
+	return p.engine.Command([]string{"show-text", text, strconv.FormatInt(d.Milliseconds(), 10)})
+}
+
+// showSkipButton mostra o botão "Pular abertura" do goanime.lua
+// Retorna erro sem o script, para o chamador usar o aviso no OSD
+func (p *Player) showSkipButton(seg SkipSegment, key string) error {
+	label := fmt.Sprintf("Pular %s", seg.Kind.label())
+	return p.sendScript("goanime-skip-button", string(seg.Kind), label, key, strconv.FormatFloat(seg.End, 'f', 3, 64))
+}
+
+// hideSkipButton esconde o botão de pular (sem efeito se o script não estiver carregado)
+func (p *Player) hideSkipButton() {
+	p.sendScript("goanime-skip-hide")
+}
+
+// showModeBanner mostra a faixa de troca de modo do goanime.lua
+func (p *Player) showModeBanner(preset *Preset, reason string) {
+	p.sendScript("goanime-mode-banner", string(preset.ID), preset.Name, preset.Icon, reason)
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseScriptRequest(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    ScriptRequestEvent
+		ok      bool // endereçado ao player
+		wantErr bool
+	}{
+		// Mensagens de outros scripts são ignoradas, sem erro
+		{nil, ScriptRequestEvent{}, false, false},
+		{[]string{}, ScriptRequestEvent{}, false, false},
+		{[]string{"uosc-version", "5.2.0"}, ScriptRequestEvent{}, false, false},
+		{[]string{"goanime", "skip-segment"}, ScriptRequestEvent{}, false, false},
+		{[]string{"Player4K", "skip-segment"}, ScriptRequestEvent{}, false, false},
+
+		// Pedidos sem argumentos
+		{[]string{"player4k", "mark-watched"}, ScriptRequestEvent{Request: ScriptMarkWatched}, true, false},
+		{[]string{"player4k", "next-episode"}, ScriptRequestEvent{Request: ScriptNextEpisode}, true, false},
+		{[]string{"player4k", "skip-segment"}, ScriptRequestEvent{Request: ScriptSkipSegment}, true, false},
+		{[]string{"player4k", "skip-segment", "extra"}, ScriptRequestEvent{Request: ScriptSkipSegment}, true, false},
+
+		// switch-mode com cada modo (sem diferenciar maiúsculas)
+		{[]string{"player4k", "switch-mode", "low"}, ScriptRequestEvent{Request: ScriptSwitchMode, Mode: ModeLow}, true, false},
+		{[]string{"player4k", "switch-mode", "medium"}, ScriptRequestEvent{Request: ScriptSwitchMode, Mode: ModeMedium}, true, false},
+		{[]string{"player4k", "switch-mode", "high"}, ScriptRequestEvent{Request: ScriptSwitchMode, Mode: ModeHigh}, true, false},
+		{[]string{"player4k", "switch-mode", " HIGH "}, ScriptRequestEvent{Request: ScriptSwitchMode, Mode: ModeHigh}, true, false},
+
+		// Endereçados ao player, mas inválidos
+		{[]string{"player4k"}, ScriptRequestEvent{}, true, true},
+		{[]string{"player4k", "switch-mode"}, ScriptRequestEvent{Request: ScriptSwitchMode}, true, true},
+		{[]string{"player4k", "switch-mode", "ultra"}, ScriptRequestEvent{Request: ScriptSwitchMode}, true, true},
+		{[]string{"player4k", "switch-mode", ""}, ScriptRequestEvent{Request: ScriptSwitchMode}, true, true},
+		{[]string{"player4k", "pause"}, ScriptRequestEvent{Request: "pause"}, true, true},
+		{[]string{"player4k", ""}, ScriptRequestEvent{}, true, true},
+	}
+
+	for _, tt := range tests {
+		got, ok, err := parseScriptRequest(tt.args)
+		if ok != tt.ok || (err != nil) != tt.wantErr {
+			t.Errorf("parseScriptRequest(%q) = ok %v, err %v; want ok %v, erro %v", tt.args, ok, err, tt.ok, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseScriptRequest(%q)\n got: %+v\nwant: %+v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestHandleClientMessage(t *testing.T) {
+	e := NewFakeEngine()
+	p := NewWithEngine(e)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := p.Subscribe(ctx)
+	runPlayer(t, p)
+
+	e.PushEvent(&EngineEvent{ID: EngineClientMessage, ClientMessage: []string{"uosc-version", "5.2.0"}})
+	e.PushEvent(&EngineEvent{ID: EngineClientMessage, ClientMessage: []string{"player4k", "switch-mode", "high"}})
+
+	req := nextEvent[ScriptRequestEvent](t, events)
+	if req.Request != ScriptSwitchMode || req.Mode != ModeHigh || req.Error != "" {
+		t.Errorf("ScriptRequestEvent = %+v, want switch-mode high sem erro", req)
+	}
+	if got := p.GetCurrentMode(); got != ModeHigh {
+		t.Errorf("GetCurrentMode = %s, want %s", got, ModeHigh)
+	}
+
+	// Fora de um trecho, skip-segment volta com o erro no evento
+	e.PushEvent(&EngineEvent{ID: EngineClientMessage, ClientMessage: []string{"player4k", "skip-segment"}})
+	req = nextEvent[ScriptRequestEvent](t, events)
+	if req.Request != ScriptSkipSegment || req.Error == "" {
+		t.Errorf("ScriptRequestEvent = %+v, want skip-segment com erro", req)
+	}
+}
@@ -0,0 +1,666 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Settings é a configuração do player em camadas:
+// embutida < player4k.json < variáveis PLAYER4K_* < opções da linha de comando
+// Cada chave é "seção.campo" com os nomes do JSON (ex: "subtitles.fontSize")
+type Settings struct {
+	Playback    PlaybackSettings   `json:"playback"`
+	Subtitles   SubtitleSettings   `json:"subtitles"`
+	OSD         OSDSettings        `json:"osd"`
+	Cache       CacheSettings      `json:"cache"`
+	Screenshots ScreenshotSettings `json:"screenshots"`
+	Network     NetworkSettings    `json:"network"`
+	Presets     PresetSettings     `json:"presets"`
+
+	sources map[string]SettingSource // camada que definiu cada chave (ausente = embutida)
+}
+
+// PlaybackSettings são as opções de reprodução
+type PlaybackSettings struct {
+	Mode          PerformanceMode  `json:"mode"` // vazio: resultado do bench ou medium
+	Volume        int              `json:"volume"`
+	VolumeMax     int              `json:"volumeMax"`
+	Speed         float64          `json:"speed"`
+	Fullscreen    bool             `json:"fullscreen"`
+	Hwdec         string           `json:"hwdec"`
+	Interpolation bool             `json:"interpolation"`
+	Adaptive      bool             `json:"adaptive"`
+	Resume        bool             `json:"resume"`
+	ConfigBundle  ConfigBundleKind `json:"configBundle"` // pasta do MPV: portable, user ou none (veja ConfigBundle)
+}
+
+// SubtitleSettings é o estilo das legendas
+type SubtitleSettings struct {
+	Font         string  `json:"font"`
+	FontSize     int     `json:"fontSize"`
+	Color        string  `json:"color"`
+	BorderColor  string  `json:"borderColor"`
+	BorderSize   float64 `json:"borderSize"`
+	ShadowColor  string  `json:"shadowColor"`
+	ShadowOffset float64 `json:"shadowOffset"`
+	MarginY      int     `json:"marginY"`
+	Blur         float64 `json:"blur"`
+	Auto         string  `json:"auto"`  // sub-auto: no, exact, fuzzy ou all
+	Paths        string  `json:"paths"` // pastas procuradas, separadas por ":"
+}
+
+// OSDSettings é o estilo das mensagens na tela
+type OSDSettings struct {
+	Font         string  `json:"font"`
+	FontSize     int     `json:"fontSize"`
+	Bold         bool    `json:"bold"`
+	Color        string  `json:"color"`
+	BorderColor  string  `json:"borderColor"`
+	BorderSize   float64 `json:"borderSize"`
+	ShadowColor  string  `json:"shadowColor"`
+	ShadowOffset float64 `json:"shadowOffset"`
+	BackColor    string  `json:"backColor"`
+	Duration     int     `json:"duration"` // ms
+	Bar          bool    `json:"bar"`
+	MarginX      int     `json:"marginX"`
+	MarginY      int     `json:"marginY"`
+}
+
+// CacheSettings controla o cache de streaming
+type CacheSettings struct {
+	Enabled       bool   `json:"enabled"`
+	MaxBytes      string `json:"maxBytes"`     // ex: "150MiB"
+	MaxBackBytes  string `json:"maxBackBytes"` // ex: "75MiB"
+	ReadaheadSecs int    `json:"readaheadSecs"`
+}
+
+// ScreenshotSettings controla as capturas de tela
+type ScreenshotSettings struct {
+	Format         string `json:"format"`
+	Directory      string `json:"directory"`
+	Template       string `json:"template"`
+	PNGCompression int    `json:"pngCompression"`
+}
+
+// NetworkSettings são as opções de rede para URLs
+type NetworkSettings struct {
+	UserAgent string `json:"userAgent"`
+	Referrer  string `json:"referrer"`
+	Proxy     string `json:"proxy"`   // http-proxy (ex: "http://127.0.0.1:8080")
+	Timeout   int    `json:"timeout"` // segundos (0 = padrão do MPV)
+}
+
+// PresetSettings escolhe presets extras e a pipeline Anime4K inicial
+type PresetSettings struct {
+	Dir   string      `json:"dir"`   // pasta com presets *.json além da "presets" ao lado do executável
+	Anime AnimePreset `json:"anime"` // pipeline Anime4K ativada ao abrir (vazio desliga)
+}
+
+// SettingSource é a camada de onde veio um valor
+type SettingSource string
+
+const (
+	SourceBuiltin SettingSource = "builtin"
+	SourceFile    SettingSource = "file"
+	SourceEnv     SettingSource = "env"
+	SourceFlag    SettingSource = "flag"
+)
+
+// SettingValue é uma chave com o valor efetivo e a camada que o definiu
+type SettingValue struct {
+	Key    string        `json:"key"`
+	Value  string        `json:"value"`
+	Source SettingSource `json:"source"`
+	Env    string        `json:"env"` // variável de ambiente equivalente
+}
+
+// DefaultSettings retorna a configuração embutida (a mesma usada antes de qualquer arquivo)
+func DefaultSettings() *Settings {
+	return &Settings{
+		Playback: PlaybackSettings{
+			Volume:        100,
+			VolumeMax:     150, // Permite volume até 150% pelo teclado
+			Speed:         1.0,
+			Hwdec:         "auto-safe",
+			Interpolation: true,
+			ConfigBundle:  BundlePortable,
+		},
+		Subtitles: SubtitleSettings{
+			Font:         platform.SubFont,
+			FontSize:     46,
+			Color:        "#FFFFFFFF",
+			BorderColor:  "#FF000000",
+			BorderSize:   2.5,
+			ShadowColor:  "#80000000",
+			ShadowOffset: 1,
+			MarginY:      40,
+			Blur:         0.2, // Leve blur nas bordas
+			Auto:         "fuzzy",
+			Paths:        "subs:subtitles:Subs:Subtitles:legendas",
+		},
+		OSD: OSDSettings{
+			Font:         platform.OSDFont,
+			FontSize:     36,
+			Bold:         true,
+			Color:        "#FFFFFFFF", // Texto branco
+			BorderColor:  "#FF6B9DFF", // Borda rosa
+			BorderSize:   2.5,
+			ShadowColor:  "#80000000", // Sombra suave
+			ShadowOffset: 2,
+			BackColor:    "#60000000", // Fundo semi-transparente
+			Duration:     2500,
+			Bar:          true,
+			MarginX:      25,
+			MarginY:      20,
+		},
+		Cache: CacheSettings{
+			Enabled:       true,
+			MaxBytes:      "150MiB",
+			MaxBackBytes:  "75MiB",
+			ReadaheadSecs: 60, // Buffer de 60s
+		},
+		Screenshots: ScreenshotSettings{
+			Format:         "png",
+			Directory:      platform.ScreenshotDir,
+			Template:       "GoAnime_%F_%P",
+			PNGCompression: 7,
+		},
+	}
+}
+
+// LoadSettings monta a configuração embutida + player4k.json + variáveis de ambiente
+// Valores inválidos não impedem o resto: a chave fica com o valor da camada anterior
+// e o problema volta no erro (as opções da linha de comando entram depois com Set)
+func LoadSettings() (*Settings, error) {
+	s := DefaultSettings()
+	var problems []string
+
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		problems = append(problems, s.applyFile(cfg)...)
+	}
+	problems = append(problems, s.applyEnv(os.LookupEnv)...)
+
+	if len(problems) > 0 {
+		return s, fmt.Errorf("configuração: %s", strings.Join(problems, "; "))
+	}
+	return s, nil
+}
+
+// applyFile aplica as seções do player4k.json
+func (s *Settings) applyFile(cfg *UserConfig) []string {
+	var problems []string
+	for section, raw := range cfg.settingsSections() {
+		if len(raw) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			problems = append(problems, fmt.Sprintf("seção %s: %v", section, err))
+			continue
+		}
+
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := string(fields[name])
+			if value == "null" {
+				continue
+			}
+			var text string
+			if json.Unmarshal(fields[name], &text) == nil {
+				value = text
+			}
+			if err := s.Set(section+"."+name, value, SourceFile); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	return problems
+}
+
+// applyEnv aplica as variáveis PLAYER4K_<SEÇÃO>_<CAMPO> (ex: PLAYER4K_OSD_FONTSIZE=40)
+func (s *Settings) applyEnv(lookup func(string) (string, bool)) []string {
+	var problems []string
+	for _, key := range SettingKeys() {
+		if value, ok := lookup(settingEnv(key)); ok {
+			if err := s.Set(key, value, SourceEnv); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", settingEnv(key), err))
+			}
+		}
+	}
+	return problems
+}
+
+// settingEnv é o nome da variável de ambiente de uma chave
+func settingEnv(key string) string {
+	return "PLAYER4K_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// SettingKeys lista todas as chaves na ordem das seções
+func SettingKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Settings{})
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if !section.IsExported() {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			keys = append(keys, jsonName(section)+"."+jsonName(section.Type.Field(j)))
+		}
+	}
+	return keys
+}
+
+// jsonName é o nome do campo no JSON
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
+
+// field encontra o campo de uma chave "seção.campo"
+func (s *Settings) field(key string) (reflect.Value, bool) {
+	sectionName, fieldName, ok := strings.Cut(key, ".")
+	if !ok {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(s).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		section := t.Field(i)
+		if !section.IsExported() || jsonName(section) != sectionName {
+			continue
+		}
+		for j := 0; j < section.Type.NumField(); j++ {
+			if jsonName(section.Type.Field(j)) == fieldName {
+				return v.Field(i).Field(j), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Set altera uma chave a partir de texto; o valor anterior fica se o novo for inválido
+func (s *Settings) Set(key, value string, source SettingSource) error {
+	f, ok := s.field(key)
+	if !ok {
+		return fmt.Errorf("chave desconhecida: %s", key)
+	}
+	old := reflect.New(f.Type()).Elem()
+	old.Set(f)
+
+	value = strings.TrimSpace(value)
+	var err error
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int:
+		var n int
+		n, err = strconv.Atoi(value)
+		f.SetInt(int64(n))
+	case reflect.Float64:
+		var x float64
+		x, err = strconv.ParseFloat(value, 64)
+		f.SetFloat(x)
+	case reflect.Bool:
+		var b bool
+		b, err = parseSettingBool(value)
+		f.SetBool(b)
+	}
+	if err == nil {
+		err = s.validate(key)
+	}
+	if err != nil {
+		f.Set(old)
+		return fmt.Errorf("%s=%q: %w", key, value, err)
+	}
+
+	if s.sources == nil {
+		s.sources = make(map[string]SettingSource)
+	}
+	s.sources[key] = source
+	return nil
+}
+
+// parseSettingBool aceita true/false, yes/no, on/off e 1/0
+func parseSettingBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("esperado true ou false")
+}
+
+// Get retorna o valor de uma chave como texto
+func (s *Settings) Get(key string) (string, bool) {
+	f, ok := s.field(key)
+	if !ok {
+		return "", false
+	}
+	switch f.Kind() {
+	case reflect.Int:
+		return strconv.FormatInt(f.Int(), 10), true
+	case reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'f', -1, 64), true
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool()), true
+	}
+	return f.String(), true
+}
+
+// Source retorna a camada que definiu a chave
+func (s *Settings) Source(key string) SettingSource {
+	if src, ok := s.sources[key]; ok {
+		return src
+	}
+	return SourceBuiltin
+}
+
+// Values lista todas as chaves com valor efetivo e origem
+func (s *Settings) Values() []SettingValue {
+	keys := SettingKeys()
+	list := make([]SettingValue, len(keys))
+	for i, key := range keys {
+		value, _ := s.Get(key)
+		list[i] = SettingValue{Key: key, Value: value, Source: s.Source(key), Env: settingEnv(key)}
+	}
+	return list
+}
+
+// Clone copia a configuração (com as origens)
+func (s *Settings) Clone() *Settings {
+	c := *s
+	c.sources = make(map[string]SettingSource, len(s.sources))
+	for k, v := range s.sources {
+		c.sources[k] = v
+	}
+	return &c
+}
+
+var (
+	settingColor = regexp.MustCompile(`^#([0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+	settingBytes = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([KMG]i?B)?$`)
+)
+
+// validate confere o valor atual de uma chave
+// O modo não é conferido aqui: presets.dir pode trazer modos que ainda não foram carregados
+func (s *Settings) validate(key string) error {
+	f, _ := s.field(key)
+	switch key {
+	case "playback.volume":
+		return checkRange(float64(s.Playback.Volume), 0, 100)
+	case "playback.volumeMax":
+		return checkRange(float64(s.Playback.VolumeMax), 100, 1000)
+	case "playback.speed":
+		return checkRange(s.Playback.Speed, 0.1, 10)
+	case "playback.hwdec", "screenshots.template":
+		if f.String() == "" {
+			return fmt.Errorf("não pode ser vazio")
+		}
+	case "subtitles.fontSize", "osd.fontSize":
+		return checkRange(float64(f.Int()), 1, 200)
+	case "subtitles.color", "subtitles.borderColor", "subtitles.shadowColor",
+		"osd.color", "osd.borderColor", "osd.shadowColor", "osd.backColor":
+		if !settingColor.MatchString(f.String()) {
+			return fmt.Errorf("cor deve ser #RRGGBB ou #AARRGGBB")
+		}
+	case "subtitles.borderSize", "subtitles.shadowOffset", "subtitles.blur",
+		"osd.borderSize", "osd.shadowOffset":
+		return checkRange(f.Float(), 0, 20)
+	case "subtitles.marginY", "osd.marginX", "osd.marginY", "osd.duration",
+		"cache.readaheadSecs", "network.timeout":
+		if f.Int() < 0 {
+			return fmt.Errorf("não pode ser negativo")
+		}
+	case "playback.configBundle":
+		if _, ok := ParseConfigBundleKind(f.String()); !ok {
+			return fmt.Errorf("use portable, user ou none")
+		}
+	case "subtitles.auto":
+		switch f.String() {
+		case "no", "exact", "fuzzy", "all":
+		default:
+			return fmt.Errorf("use no, exact, fuzzy ou all")
+		}
+	case "cache.maxBytes", "cache.maxBackBytes":
+		if !settingBytes.MatchString(f.String()) {
+			return fmt.Errorf("tamanho deve ser como 150MiB ou 1GiB")
+		}
+	case "screenshots.format":
+		switch f.String() {
+		case "png", "jpg", "jpeg", "webp", "jxl", "avif":
+		default:
+			return fmt.Errorf("use png, jpg, webp, jxl ou avif")
+		}
+	case "screenshots.pngCompression":
+		return checkRange(float64(s.Screenshots.PNGCompression), 0, 9)
+	case "network.proxy":
+		if p := s.Network.Proxy; p != "" {
+			if u, err := url.Parse(p); err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("proxy deve ser uma URL (http://host:porta)")
+			}
+		}
+	case "presets.anime":
+		if id := s.Presets.Anime; id != "" {
+			if _, ok := ParseAnimePreset(string(id)); !ok {
+				return fmt.Errorf("preset Anime4K desconhecido")
+			}
+		}
+	}
+	return nil
+}
+
+// checkRange confere se v está entre min e max
+func checkRange(v, min, max float64) error {
+	if v < min || v > max {
+		return fmt.Errorf("deve estar entre %g e %g", min, max)
+	}
+	return nil
+}
+
+// mpvProperties são as propriedades do MPV de uma chave (nenhuma para as que
+// o Player aplica por conta própria, como modo, volume e Anime4K)
+func (s *Settings) mpvProperties(key string) PropertyList {
+	yes := boolToYesNo
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+	switch key {
+	case "playback.volumeMax":
+		return PropertyList{{"volume-max", itoa(s.Playback.VolumeMax)}}
+	case "playback.speed":
+		return PropertyList{{"speed", ftoa(s.Playback.Speed)}}
+	case "playback.fullscreen":
+		return PropertyList{{"fullscreen", yes(s.Playback.Fullscreen)}}
+	case "playback.hwdec":
+		return PropertyList{{"hwdec", s.Playback.Hwdec}}
+	case "playback.interpolation":
+		if s.Playback.Interpolation {
+			return PropertyList{{"video-sync", "display-resample"}, {"interpolation", "yes"}, {"tscale", "oversample"}}
+		}
+		return PropertyList{{"interpolation", "no"}, {"video-sync", "audio"}}
+
+	case "subtitles.font":
+		return PropertyList{{"sub-font", s.Subtitles.Font}}
+	case "subtitles.fontSize":
+		return PropertyList{{"sub-font-size", itoa(s.Subtitles.FontSize)}}
+	case "subtitles.color":
+		return PropertyList{{"sub-color", s.Subtitles.Color}}
+	case "subtitles.borderColor":
+		return PropertyList{{"sub-border-color", s.Subtitles.BorderColor}}
+	case "subtitles.borderSize":
+		return PropertyList{{"sub-border-size", ftoa(s.Subtitles.BorderSize)}}
+	case "subtitles.shadowColor":
+		return PropertyList{{"sub-shadow-color", s.Subtitles.ShadowColor}}
+	case "subtitles.shadowOffset":
+		return PropertyList{{"sub-shadow-offset", ftoa(s.Subtitles.ShadowOffset)}}
+	case "subtitles.marginY":
+		return PropertyList{{"sub-margin-y", itoa(s.Subtitles.MarginY)}}
+	case "subtitles.blur":
+		return PropertyList{{"sub-blur", ftoa(s.Subtitles.Blur)}}
+	case "subtitles.auto":
+		return PropertyList{{"sub-auto", s.Subtitles.Auto}}
+	case "subtitles.paths":
+		return PropertyList{{"sub-file-paths", s.Subtitles.Paths}}
+
+	case "osd.font":
+		return PropertyList{{"osd-font", s.OSD.Font}}
+	case "osd.fontSize":
+		return PropertyList{{"osd-font-size", itoa(s.OSD.FontSize)}}
+	case "osd.bold":
+		return PropertyList{{"osd-bold", yes(s.OSD.Bold)}}
+	case "osd.color":
+		return PropertyList{{"osd-color", s.OSD.Color}}
+	case "osd.borderColor":
+		return PropertyList{{"osd-border-color", s.OSD.BorderColor}}
+	case "osd.borderSize":
+		return PropertyList{{"osd-border-size", ftoa(s.OSD.BorderSize)}}
+	case "osd.shadowColor":
+		return PropertyList{{"osd-shadow-color", s.OSD.ShadowColor}}
+	case "osd.shadowOffset":
+		return PropertyList{{"osd-shadow-offset", ftoa(s.OSD.ShadowOffset)}}
+	case "osd.backColor":
+		return PropertyList{{"osd-back-color", s.OSD.BackColor}}
+	case "osd.duration":
+		return PropertyList{{"osd-duration", itoa(s.OSD.Duration)}}
+	case "osd.bar":
+		return PropertyList{{"osd-bar", yes(s.OSD.Bar)}}
+	case "osd.marginX":
+		return PropertyList{{"osd-margin-x", itoa(s.OSD.MarginX)}}
+	case "osd.marginY":
+		return PropertyList{{"osd-margin-y", itoa(s.OSD.MarginY)}}
+
+	case "cache.enabled":
+		return PropertyList{{"cache", yes(s.Cache.Enabled)}}
+	case "cache.maxBytes":
+		return PropertyList{{"demuxer-max-bytes", s.Cache.MaxBytes}}
+	case "cache.maxBackBytes":
+		return PropertyList{{"demuxer-max-back-bytes", s.Cache.MaxBackBytes}}
+	case "cache.readaheadSecs":
+		return PropertyList{{"demuxer-readahead-secs", itoa(s.Cache.ReadaheadSecs)}}
+
+	case "screenshots.format":
+		return PropertyList{{"screenshot-format", s.Screenshots.Format}}
+	case "screenshots.directory":
+		return PropertyList{{"screenshot-directory", s.Screenshots.Directory}}
+	case "screenshots.template":
+		return PropertyList{{"screenshot-template", s.Screenshots.Template}}
+	case "screenshots.pngCompression":
+		return PropertyList{{"screenshot-png-compression", itoa(s.Screenshots.PNGCompression)}}
+
+	case "network.userAgent":
+		if s.Network.UserAgent != "" {
+			return PropertyList{{"user-agent", s.Network.UserAgent}}
+		}
+	case "network.referrer":
+		return PropertyList{{"referrer", s.Network.Referrer}}
+	case "network.proxy":
+		return PropertyList{{"http-proxy", s.Network.Proxy}}
+	case "network.timeout":
+		if s.Network.Timeout > 0 {
+			return PropertyList{{"network-timeout", itoa(s.Network.Timeout)}}
+		}
+	}
+	return nil
+}
+
+// --- Integração com o Player ---
+
+// applySettingsProperties envia ao MPV as propriedades das chaves escolhidas
+// As que trocam o mpv.conf do pacote ficam em BundleConflicts
+func (p *Player) applySettingsProperties(s *Settings, keys []string) {
+	conflicts := []BundleConflict{}
+	for _, key := range keys {
+		props := s.mpvProperties(key)
+		for _, prop := range props {
+			if err := p.engine.SetPropertyString(prop.Name, prop.Value); err != nil {
+				fmt.Printf("  ⚠️ %s=%s (%s): %v\n", prop.Name, prop.Value, key, err)
+			}
+		}
+		conflicts = append(conflicts, p.bundle.Conflicts(props, fmt.Sprintf("%s (%s)", key, s.Source(key)))...)
+	}
+
+	p.mu.Lock()
+	p.recordBundleConflicts(overrideSettings, conflicts)
+	p.mu.Unlock()
+}
+
+// ApplySettings aplica uma configuração montada por LoadSettings (e Set)
+// Só as chaves que não são embutidas vão para o MPV: os padrões já foram aplicados
+// na criação do player e não devem desfazer as propriedades do modo atual
+func (p *Player) ApplySettings(s *Settings) error {
+	var problems []string
+
+	if s.Presets.Dir != "" {
+		ensureUserPresets() // os presets de presets.dir vencem os da pasta padrão
+		if err := LoadPresets(s.Presets.Dir); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	// Modo antes das propriedades: hwdec/interpolation do usuário vencem as do preset
+	if s.Playback.Mode != "" {
+		mode, ok := ParsePerformanceMode(string(s.Playback.Mode))
+		if !ok {
+			problems = append(problems, fmt.Sprintf("playback.mode: modo desconhecido %q, usando medium", s.Playback.Mode))
+			mode = ModeMedium
+		}
+		if err := p.SetPerformanceMode(mode); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if s.Presets.Anime != "" {
+		if err := p.SetAnimePreset(s.Presets.Anime); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	var keys []string
+	for _, key := range SettingKeys() {
+		if s.Source(key) != SourceBuiltin {
+			keys = append(keys, key)
+		}
+	}
+	p.applySettingsProperties(s, keys)
+
+	if s.Playback.Volume != p.GetVolume() {
+		p.SetVolume(s.Playback.Volume)
+	}
+	p.EnableAdaptiveQuality(s.Playback.Adaptive)
+	p.SetAutoResume(s.Playback.Resume)
+
+	p.mu.Lock()
+	p.settings = s.Clone()
+	p.mu.Unlock()
+
+	if len(problems) > 0 {
+		return fmt.Errorf("configuração: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Settings retorna a última configuração aplicada (a embutida se nenhuma foi)
+func (p *Player) Settings() *Settings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.settings == nil {
+		return DefaultSettings()
+	}
+	return p.settings.Clone()
+}
@@ -0,0 +1,305 @@
+package player
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Manifesto embutido com os shaders distribuídos junto com o player
+//
+//go:embed shaders.json
+var builtinShaderManifest []byte
+
+// ShaderCost indica o custo de GPU de um shader
+type ShaderCost string
+
+const (
+	CostLow    ShaderCost = "low"
+	CostMedium ShaderCost = "medium"
+	CostHigh   ShaderCost = "high"
+	CostUltra  ShaderCost = "ultra"
+)
+
+// ShaderEntry descreve um shader conhecido
+type ShaderEntry struct {
+	Name     string     `json:"name"`
+	Path     string     `json:"path"` // relativo à pasta shaders/
+	SHA256   string     `json:"sha256,omitempty"`
+	License  string     `json:"license"`
+	Upstream string     `json:"upstream"`
+	Cost     ShaderCost `json:"cost"`
+	Fallback string     `json:"fallback,omitempty"` // nome do shader usado se este faltar
+	Optional bool       `json:"optional,omitempty"`
+}
+
+// ShaderManifest é a lista de shaders conhecidos
+type ShaderManifest struct {
+	Version int           `json:"version"`
+	Shaders []ShaderEntry `json:"shaders"`
+}
+
+// ShaderStatus é o resultado da verificação de um shader
+type ShaderStatus string
+
+const (
+	ShaderOK       ShaderStatus = "ok"
+	ShaderMissing  ShaderStatus = "missing"
+	ShaderCorrupt  ShaderStatus = "corrupt"
+	ShaderUnlisted ShaderStatus = "unlisted" // existe, mas não está no manifesto
+)
+
+// ShaderCheck é a verificação de um shader
+type ShaderCheck struct {
+	Name     string       `json:"name"`
+	Path     string       `json:"path"`
+	Status   ShaderStatus `json:"status"`
+	Expected string       `json:"expected,omitempty"`
+	Actual   string       `json:"actual,omitempty"`
+	Optional bool         `json:"optional,omitempty"`
+	Fallback string       `json:"fallback,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// ShaderReport é o relatório de verificação da pasta de shaders
+type ShaderReport struct {
+	Dir     string        `json:"dir"`
+	Checks  []ShaderCheck `json:"checks"`
+	OK      int           `json:"ok"`
+	Missing int           `json:"missing"`
+	Corrupt int           `json:"corrupt"`
+}
+
+// Healthy indica se nenhum shader obrigatório está faltando ou corrompido
+func (r ShaderReport) Healthy() bool {
+	for _, c := range r.Problems() {
+		if !c.Optional {
+			return false
+		}
+	}
+	return true
+}
+
+// Problems retorna apenas os shaders faltando ou corrompidos
+func (r ShaderReport) Problems() []ShaderCheck {
+	var problems []ShaderCheck
+	for _, c := range r.Checks {
+		if c.Status == ShaderMissing || c.Status == ShaderCorrupt {
+			problems = append(problems, c)
+		}
+	}
+	return problems
+}
+
+// LoadShaderManifest carrega o manifesto de uma pasta de shaders
+// Usa shaders/manifest.json se existir, senão o manifesto embutido
+func LoadShaderManifest(dir string) (*ShaderManifest, error) {
+	data := builtinShaderManifest
+	source := "builtin"
+
+	custom := filepath.Join(dir, "manifest.json")
+	if b, err := os.ReadFile(custom); err == nil {
+		data = b
+		source = custom
+	}
+
+	var m ShaderManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifesto de shaders %s: %w", source, err)
+	}
+	return &m, nil
+}
+
+// Lookup procura um shader pelo nome ou pelo caminho relativo
+func (m *ShaderManifest) Lookup(ref string) (ShaderEntry, bool) {
+	ref = filepath.ToSlash(ref)
+	for _, e := range m.Shaders {
+		if e.Name == ref || e.Path == ref || e.Name == strings.TrimSuffix(ref, ".glsl") {
+			return e, true
+		}
+	}
+	return ShaderEntry{}, false
+}
+
+// DefaultShaderDir retorna a pasta "shaders" do diretório atual
+func DefaultShaderDir() string {
+	execPath, _ := filepath.Abs(".")
+	return filepath.Join(execPath, "shaders")
+}
+
+// VerifyShaderDir verifica todos os shaders do manifesto em uma pasta
+// Arquivos .glsl presentes mas fora do manifesto aparecem como "unlisted"
+func VerifyShaderDir(dir string) (ShaderReport, error) {
+	manifest, err := LoadShaderManifest(dir)
+	if err != nil {
+		return ShaderReport{Dir: dir}, err
+	}
+	return manifest.Verify(dir), nil
+}
+
+// Verify verifica os shaders do manifesto na pasta informada
+func (m *ShaderManifest) Verify(dir string) ShaderReport {
+	report := ShaderReport{Dir: dir}
+	listed := make(map[string]bool)
+
+	for _, e := range m.Shaders {
+		listed[e.Path] = true
+		report.add(checkShader(dir, e))
+	}
+
+	// Shaders soltos na pasta que o manifesto não conhece
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".glsl") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+		if !listed[rel] {
+			report.add(ShaderCheck{
+				Name:   strings.TrimSuffix(filepath.Base(rel), ".glsl"),
+				Path:   rel,
+				Status: ShaderUnlisted,
+			})
+		}
+		return nil
+	})
+
+	return report
+}
+
+func (r *ShaderReport) add(c ShaderCheck) {
+	switch c.Status {
+	case ShaderOK:
+		r.OK++
+	case ShaderMissing:
+		r.Missing++
+	case ShaderCorrupt:
+		r.Corrupt++
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// checkShader verifica existência e SHA-256 de um shader
+func checkShader(dir string, e ShaderEntry) ShaderCheck {
+	c := ShaderCheck{
+		Name:     e.Name,
+		Path:     e.Path,
+		Expected: e.SHA256,
+		Optional: e.Optional,
+		Fallback: e.Fallback,
+	}
+
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(e.Path)))
+	if err != nil {
+		c.Status = ShaderMissing
+		if !os.IsNotExist(err) {
+			c.Error = err.Error()
+		}
+		return c
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		c.Status = ShaderCorrupt
+		c.Error = err.Error()
+		return c
+	}
+	c.Actual = hex.EncodeToString(h.Sum(nil))
+
+	if e.SHA256 != "" && !strings.EqualFold(c.Actual, e.SHA256) {
+		c.Status = ShaderCorrupt
+		return c
+	}
+
+	c.Status = ShaderOK
+	return c
+}
+
+// resolveShaders transforma referências (nome ou caminho) em caminhos válidos
+// Shaders faltando ou corrompidos são trocados pelo fallback do manifesto
+// Retorna os caminhos absolutos e a verificação dos que tiveram problema
+func (m *ShaderManifest) resolveShaders(dir string, refs []string) ([]string, []ShaderCheck) {
+	var paths []string
+	var problems []ShaderCheck
+
+	for _, ref := range refs {
+		entry, ok := m.Lookup(ref)
+		if !ok {
+			// Shader fora do manifesto: usa se existir, sem checar integridade
+			path := filepath.Join(dir, filepath.FromSlash(ref))
+			if _, err := os.Stat(path); err != nil {
+				problems = append(problems, ShaderCheck{Name: ref, Path: ref, Status: ShaderMissing})
+				continue
+			}
+			paths = append(paths, path)
+			continue
+		}
+
+		// Segue a cadeia de fallbacks até achar um shader válido
+		seen := make(map[string]bool)
+		for {
+			check := checkShader(dir, entry)
+			if check.Status == ShaderOK {
+				paths = append(paths, filepath.Join(dir, filepath.FromSlash(entry.Path)))
+				break
+			}
+			problems = append(problems, check)
+			seen[entry.Name] = true
+
+			next, ok := m.Lookup(entry.Fallback)
+			if entry.Fallback == "" || !ok || seen[next.Name] {
+				break
+			}
+			entry = next
+		}
+	}
+
+	return paths, problems
+}
+
+// VerifyShaders verifica a pasta de shaders do player e guarda o relatório
+func (p *Player) VerifyShaders() ShaderReport {
+	report := p.shaders.Verify(p.shaderPath)
+
+	p.mu.Lock()
+	p.shaderReport = report
+	p.mu.Unlock()
+
+	return report
+}
+
+// GetShaderReport retorna o último relatório de verificação
+func (p *Player) GetShaderReport() ShaderReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.shaderReport
+}
+
+// appendShaders valida uma cadeia de shaders e adiciona ao MPV
+// Deve ser chamado com p.mu travado
+func (p *Player) appendShaders(refs []string) {
+	paths, problems := p.shaders.resolveShaders(p.shaderPath, refs)
+
+	for _, c := range problems {
+		if c.Fallback != "" {
+			fmt.Printf("  ⚠️ Shader %s (%s), tentando fallback %s\n", c.Name, c.Status, c.Fallback)
+		} else if !c.Optional {
+			fmt.Printf("  ⚠️ Shader %s (%s): %s\n", c.Name, c.Status, c.Path)
+		}
+	}
+
+	for _, path := range paths {
+		err := p.engine.Command([]string{"change-list", "glsl-shaders", "append", path})
+		if err != nil {
+			fmt.Printf("  ⚠️ Shader não carregado: %s\n", path)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SegmentKind é o tipo de um trecho que pode ser pulado
+type SegmentKind string
+
+const (
+	SegmentOpening SegmentKind = "op"      // abertura
+	SegmentEnding  SegmentKind = "ed"      // encerramento
+	SegmentRecap   SegmentKind = "recap"   // recapitulação
+	SegmentPreview SegmentKind = "preview" // prévia do próximo episódio
+)
+
+// SkipMode define o que fazer ao entrar em um trecho
+type SkipMode string
+
+const (
+	SkipOff    SkipMode = "off"    // ignora o trecho
+	SkipPrompt SkipMode = "prompt" // mostra "Pular abertura [tecla]" e espera o usuário
+	SkipAuto   SkipMode = "auto"   // pula sozinho (uma vez por arquivo)
+)
+
+// defaultSkipKey é a tecla do aviso de pular quando o input.conf não liga skip-segment
+// (é a do input.conf solto ao lado do executável; o pacote usa a do input.conf dele)
+const defaultSkipKey = "i"
+
+// skipSection é a seção de atalhos do MPV ligada enquanto o aviso está na tela
+const skipSection = "goanime-skip"
+
+// SkipSegment é um trecho do episódio que pode ser pulado
+type SkipSegment struct {
+	Kind   SegmentKind `json:"kind"`
+	Start  float64     `json:"start"`
+	End    float64     `json:"end"`
+	Source string      `json:"source,omitempty"` // "chapters", "aniskip", "gui"...
+}
+
+// Contains indica se a posição está dentro do trecho
+// O último meio segundo fica de fora para não pular de novo ao chegar no fim
+func (s SkipSegment) Contains(pos float64) bool {
+	return pos >= s.Start && pos < s.End-0.5
+}
+
+// label é o nome do trecho nos avisos na tela
+func (k SegmentKind) label() string {
+	switch k {
+	case SegmentOpening:
+		return "abertura"
+	case SegmentEnding:
+		return "encerramento"
+	case SegmentRecap:
+		return "recapitulação"
+	case SegmentPreview:
+		return "prévia"
+	}
+	return string(k)
+}
+
+// SkipPreferences escolhe o modo de cada tipo de trecho
+type SkipPreferences struct {
+	Mode  SkipMode                 `json:"mode,omitempty"`  // padrão para todos (prompt se vazio)
+	Kinds map[SegmentKind]SkipMode `json:"kinds,omitempty"` // ex: {"op": "auto", "preview": "off"}
+}
+
+// ModeFor retorna o modo de um tipo de trecho
+func (s SkipPreferences) ModeFor(kind SegmentKind) SkipMode {
+	if mode, ok := s.Kinds[kind]; ok && mode != "" {
+		return mode
+	}
+	if s.Mode != "" {
+		return s.Mode
+	}
+	return SkipPrompt
+}
+
+// ParseSkipMode valida um modo vindo da linha de comando ou do GUI
+func ParseSkipMode(s string) (SkipMode, error) {
+	switch mode := SkipMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case SkipOff, SkipPrompt, SkipAuto:
+		return mode, nil
+	}
+	return "", fmt.Errorf("modo de pular desconhecido: %s (off, prompt ou auto)", s)
+}
+
+// --- Detecção pelos capítulos ---
+
+// reChapterWord separa o título do capítulo em palavras
+var reChapterWord = regexp.MustCompile(`[\p{L}\d]+`)
+
+// chapterKind classifica um capítulo pelo título (Opening, OP, Intro, ED, Ending, Preview...)
+func chapterKind(title string) (SegmentKind, bool) {
+	words := reChapterWord.FindAllString(strings.ToLower(title), -1)
+	has := func(match func(w string) bool) bool {
+		for _, w := range words {
+			if match(w) {
+				return true
+			}
+		}
+		return false
+	}
+	numbered := func(prefix string) func(string) bool {
+		return func(w string) bool {
+			return w == prefix || (strings.HasPrefix(w, prefix) && strings.Trim(w[len(prefix):], "0123456789") == "")
+		}
+	}
+	oneOf := func(list ...string) func(string) bool {
+		return func(w string) bool {
+			for _, s := range list {
+				if w == s {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	switch {
+	case has(numbered("op")) || has(oneOf("opening", "intro", "abertura")):
+		return SegmentOpening, true
+	case has(numbered("ed")) || has(oneOf("ending", "outro", "credits", "encerramento")):
+		return SegmentEnding, true
+	case has(oneOf("preview", "yokoku", "prévia", "previa")) || strings.Contains(strings.Join(words, " "), "next episode"):
+		return SegmentPreview, true
+	case has(oneOf("recap", "previously", "recapitulação")):
+		return SegmentRecap, true
+	}
+	return "", false
+}
+
+// SegmentsFromChapters monta os trechos a partir dos capítulos do arquivo
+// Cada trecho vai até o próximo capítulo (ou até o fim do arquivo)
+func SegmentsFromChapters(chapters []Chapter, duration float64) []SkipSegment {
+	var segments []SkipSegment
+	for i, c := range chapters {
+		kind, ok := chapterKind(c.Title)
+		if !ok {
+			continue
+		}
+		end := duration
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Time
+		}
+		if end <= c.Time {
+			continue
+		}
+		segments = append(segments, SkipSegment{Kind: kind, Start: c.Time, End: end, Source: "chapters"})
+	}
+	return segments
+}
+
+// --- AniSkip ---
+
+// aniSkipResponse é o formato da API do AniSkip (v2 /skip-times)
+type aniSkipResponse struct {
+	Found   bool `json:"found"`
+	Results []struct {
+		Interval struct {
+			StartTime float64 `json:"startTime"`
+			EndTime   float64 `json:"endTime"`
+		} `json:"interval"`
+		SkipType string `json:"skipType"`
+	} `json:"results"`
+}
+
+// ParseAniSkip lê trechos no formato do AniSkip (op, ed, mixed-op, mixed-ed, recap)
+func ParseAniSkip(data []byte) ([]SkipSegment, error) {
+	var resp aniSkipResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("JSON do AniSkip inválido: %w", err)
+	}
+
+	var segments []SkipSegment
+	for _, r := range resp.Results {
+		var kind SegmentKind
+		switch r.SkipType {
+		case "op", "mixed-op":
+			kind = SegmentOpening
+		case "ed", "mixed-ed":
+			kind = SegmentEnding
+		case "recap":
+			kind = SegmentRecap
+		case "preview":
+			kind = SegmentPreview
+		default:
+			continue
+		}
+		if r.Interval.EndTime <= r.Interval.StartTime {
+			continue
+		}
+		segments = append(segments, SkipSegment{
+			Kind:   kind,
+			Start:  r.Interval.StartTime,
+			End:    r.Interval.EndTime,
+			Source: "aniskip",
+		})
+	}
+	return segments, nil
+}
+
+// LoadAniSkipFile lê um arquivo JSON no formato do AniSkip
+func LoadAniSkipFile(path string) ([]SkipSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := ParseAniSkip(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return segments, nil
+}
+
+// mergeSegments junta os trechos externos com os detectados
+// Os externos vencem: detectados que se sobrepõem a eles são descartados
+func mergeSegments(external, detected []SkipSegment) []SkipSegment {
+	merged := append([]SkipSegment(nil), external...)
+	for _, c := range detected {
+		overlaps := false
+		for _, e := range external {
+			if c.Start < e.End && e.Start < c.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			merged = append(merged, c)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Start < merged[j].Start })
+	return merged
+}
+
+// --- Integração com o Player ---
+
+// skipState são os trechos do arquivo atual e o que já foi feito com eles
+type skipState struct {
+	prefs        SkipPreferences
+	path         string // arquivo atual (LoadFile/LoadURL, start-file)
+	external     []SkipSegment
+	externalPath string        // arquivo a que os trechos externos pertencem
+	detected     []SkipSegment // capítulos ou, sem eles, impressão digital do áudio (intro.go)
+	segments     []SkipSegment // externos + detectados, em ordem
+	active       int           // trecho em que a reprodução está (-1 fora)
+	skipped      map[int]bool  // trechos já pulados automaticamente
+	key          string        // tecla de skip-segment no input.conf em uso
+}
+
+// SetSkipPreferences define o que fazer em cada tipo de trecho
+func (p *Player) SetSkipPreferences(prefs SkipPreferences) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.skip.prefs = prefs
+}
+
+// SkipPreferences retorna as preferências de pular trechos
+func (p *Player) SkipPreferences() SkipPreferences {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.skip.prefs
+}
+
+// SetSkipSegments define os trechos do arquivo atual (GUI, AniSkip...)
+// Valem só para este arquivo e substituem os capítulos que se sobrepõem a eles
+func (p *Player) SetSkipSegments(segments []SkipSegment) {
+	p.mu.Lock()
+	p.skip.external = append([]SkipSegment(nil), segments...)
+	p.skip.externalPath = p.skip.path
+	ev := p.rebuildSegments()
+	p.mu.Unlock()
+
+	p.emit(ev)
+}
+
+// SkipSegments retorna os trechos do arquivo atual
+func (p *Player) SkipSegments() []SkipSegment {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]SkipSegment(nil), p.skip.segments...)
+}
+
+// SkipCurrentSegment pula o trecho em que a reprodução está
+func (p *Player) SkipCurrentSegment() error {
+	p.mu.Lock()
+	i := p.skip.active
+	if i < 0 || i >= len(p.skip.segments) {
+		p.mu.Unlock()
+		return fmt.Errorf("nenhum trecho para pular na posição atual")
+	}
+	seg := p.skip.segments[i]
+	p.mu.Unlock()
+
+	p.skipTo(seg, SkipPrompt)
+	return nil
+}
+
+// rebuildSegments recalcula a lista de trechos (chamar com p.mu travado)
+func (p *Player) rebuildSegments() SkipSegmentsEvent {
+	p.skip.segments = mergeSegments(p.skip.external, p.skip.detected)
+	p.skip.active = -1
+	p.skip.skipped = make(map[int]bool)
+	return SkipSegmentsEvent{Segments: append([]SkipSegment{}, p.skip.segments...)}
+}
+
+// setSkipPath registra o arquivo que está abrindo (trechos externos são por arquivo)
+func (p *Player) setSkipPath(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.skip.path = path
+}
+
+// loadSkipSegments lê os capítulos (ou o cache de aberturas) do arquivo que acabou de abrir
+func (p *Player) loadSkipSegments(loaded FileLoadedEvent) {
+	chapters, _ := parseChapterList(p.getString("chapter-list"))
+	detected := SegmentsFromChapters(chapters, loaded.Duration)
+	if len(detected) == 0 {
+		detected = p.cachedSegments(loaded.Path)
+	}
+
+	p.mu.Lock()
+	p.skip.path = loaded.Path
+	if p.skip.externalPath != loaded.Path {
+		p.skip.external = nil
+	}
+	p.skip.detected = detected
+	ev := p.rebuildSegments()
+	p.mu.Unlock()
+
+	p.engine.Command([]string{"disable-section", skipSection})
+	if len(ev.Segments) > 0 {
+		fmt.Printf("⏭️ %d trecho(s) para pular\n", len(ev.Segments))
+	}
+	p.emit(ev)
+}
+
+// updateSkip acompanha a posição: avisa, pula ou esconde o aviso ao entrar/sair de um trecho
+func (p *Player) updateSkip(pos float64) {
+	p.mu.Lock()
+	active := -1
+	for i, seg := range p.skip.segments {
+		if seg.Contains(pos) {
+			active = i
+			break
+		}
+	}
+	if active == p.skip.active {
+		p.mu.Unlock()
+		return
+	}
+	previous := p.skip.active
+	p.skip.active = active
+
+	var seg SkipSegment
+	mode := SkipOff
+	if active >= 0 {
+		seg = p.skip.segments[active]
+		mode = p.skip.prefs.ModeFor(seg.Kind)
+		if mode == SkipAuto && p.skip.skipped[active] {
+			// Usuário voltou para um trecho já pulado: só avisa
+			mode = SkipPrompt
+		}
+		if mode == SkipAuto {
+			p.skip.skipped[active] = true
+		}
+	}
+	var left SkipSegment
+	if previous >= 0 && previous < len(p.skip.segments) {
+		left = p.skip.segments[previous]
+	}
+	key := p.skip.key
+	p.mu.Unlock()
+
+	if previous >= 0 {
+		p.engine.Command([]string{"disable-section", skipSection})
+		p.hideSkipButton()
+		p.emit(SegmentEvent{Action: "leave", Segment: left})
+	}
+	if active < 0 {
+		return
+	}
+
+	p.emit(SegmentEvent{Action: "enter", Segment: seg, Mode: mode})
+	switch mode {
+	case SkipAuto:
+		p.skipTo(seg, mode)
+	case SkipPrompt:
+		// A tecla é a mesma de skip-segment no input.conf: enquanto o aviso está ativo
+		// pula até o fim exato do trecho; fora dele volta o atalho do input.conf
+		bind := fmt.Sprintf("%s seek %.3f absolute+exact; show-text \"⏭️ Pulando %s\"\n", key, seg.End, seg.Kind.label())
+		p.engine.Command([]string{"define-section", skipSection, bind, "force"})
+		p.engine.Command([]string{"enable-section", skipSection})
+		if err := p.showSkipButton(seg, key); err != nil {
+			// Sem o goanime.lua: aviso no OSD comum
+			p.engine.Command([]string{"show-text", fmt.Sprintf("⏭️ Pular %s [%s]", seg.Kind.label(), key), "5000"})
+		}
+	}
+}
+
+// skipTo pula para o fim do trecho
+func (p *Player) skipTo(seg SkipSegment, mode SkipMode) {
+	p.engine.Command([]string{"disable-section", skipSection})
+	p.engine.Command([]string{"seek", fmt.Sprintf("%.3f", seg.End), "absolute+exact"})
+	p.engine.Command([]string{"show-text", fmt.Sprintf("⏭️ Pulando %s", seg.Kind.label()), "2000"})
+	fmt.Printf("⏭️ Pulando %s (%s → %s)\n", seg.Kind.label(), formatClock(seg.Start), formatClock(seg.End))
+	p.emit(SegmentEvent{Action: "skipped", Segment: seg, Mode: mode})
+}
@@ -0,0 +1,122 @@
+package player
+
+// PlayerState é o estado da reprodução derivado das propriedades do MPV
+type PlayerState string
+
+const (
+	StateIdle      PlayerState = "idle"      // nenhum arquivo carregado
+	StateLoading   PlayerState = "loading"   // loadfile enviado, arquivo ainda abrindo
+	StatePlaying   PlayerState = "playing"   // reproduzindo
+	StatePaused    PlayerState = "paused"    // pausado pelo usuário (GUI, ESPAÇO na janela...)
+	StateBuffering PlayerState = "buffering" // parado esperando o cache (paused-for-cache)
+	StateSeeking   PlayerState = "seeking"   // seek em andamento
+	StateEnded     PlayerState = "ended"     // chegou ao fim (keep-open mantém o último frame)
+	StateError     PlayerState = "error"     // o arquivo não pôde ser reproduzido
+)
+
+// stateInputs são os sinais usados para calcular o estado
+// Vêm dos observers (pause, idle-active, seeking, paused-for-cache, eof-reached)
+// e dos eventos start-file/file-loaded/end-file
+type stateInputs struct {
+	Idle       bool
+	Loading    bool
+	Paused     bool
+	Buffering  bool
+	Seeking    bool
+	EOFReached bool // eof-reached (com keep-open=yes o MPV não emite end-file no fim)
+	Ended      bool // end-file com motivo eof
+	Failed     bool // end-file com motivo error
+}
+
+// deriveState calcula o estado a partir dos sinais
+// A ordem importa: erro e carregamento têm prioridade, pausa é o último recurso
+func deriveState(in stateInputs) PlayerState {
+	switch {
+	case in.Failed:
+		return StateError
+	case in.Loading:
+		return StateLoading
+	case in.Ended || in.EOFReached:
+		return StateEnded
+	case in.Idle:
+		return StateIdle
+	case in.Seeking:
+		return StateSeeking
+	case in.Buffering:
+		return StateBuffering
+	case in.Paused:
+		return StatePaused
+	}
+	return StatePlaying
+}
+
+// State retorna o estado atual da reprodução
+func (p *Player) State() PlayerState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.snapshot.State
+}
+
+// refreshState recalcula o estado; deve ser chamado com p.mu travado
+// Retorna o evento a publicar (depois de soltar o lock) ou nil se nada mudou
+func (p *Player) refreshState() Event {
+	s := &p.snapshot
+	p.stateIn.Paused = s.Paused
+	p.stateIn.Buffering = s.Buffering
+	p.stateIn.Seeking = s.Seeking
+	p.stateIn.Idle = s.Idle
+	p.stateIn.EOFReached = s.EOFReached
+
+	next := deriveState(p.stateIn)
+	if next == s.State {
+		return nil
+	}
+	ev := StateChangedEvent{State: next, Previous: s.State}
+	s.State = next
+	return ev
+}
+
+// updateState altera os sinais com o lock travado e publica a mudança de estado
+func (p *Player) updateState(change func(in *stateInputs, s *PlaybackSnapshot)) {
+	p.mu.Lock()
+	change(&p.stateIn, &p.snapshot)
+	ev := p.refreshState()
+	p.mu.Unlock()
+
+	if ev != nil {
+		p.emit(ev)
+	}
+}
+
+// handleStartFile marca o início do carregamento (loadfile, playlist, redirect)
+func (p *Player) handleStartFile() {
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		in.Loading, in.Ended, in.Failed = true, false, false
+		s.EOFReached = false
+	})
+}
+
+// handleFileLoaded encerra o carregamento
+func (p *Player) handleFileLoaded() {
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		in.Loading = false
+	})
+}
+
+// handleEndState aplica o motivo do end-file ao estado
+func (p *Player) handleEndState(reason EndReason) {
+	p.updateState(func(in *stateInputs, s *PlaybackSnapshot) {
+		switch reason {
+		case EndFileEOF:
+			in.Loading, in.Ended = false, true
+		case EndFileError:
+			in.Loading, in.Failed = false, true
+		case EndFileStop, EndFileQuit:
+			// idle-active (ou o start-file do próximo arquivo) define o estado
+			in.Loading = false
+		case EndFileRedirect:
+			// O MPV já vai carregar a nova URL: continua em loading
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Tipos de faixa usados pelo MPV em track-list
+const (
+	TrackVideo    = "video"
+	TrackAudio    = "audio"
+	TrackSubtitle = "sub"
+)
+
+// Track é uma faixa de vídeo, áudio ou legenda (item de track-list do MPV)
+// As tags JSON seguem o padrão da GUI; o formato do MPV fica em mpvTrack
+type Track struct {
+	ID         int64   `json:"id"`   // valor usado em SetAudioTrack/SetSubtitleTrack
+	Type       string  `json:"type"` // TrackVideo, TrackAudio ou TrackSubtitle
+	Label      string  `json:"label"`
+	Title      string  `json:"title,omitempty"`
+	Lang       string  `json:"lang,omitempty"`
+	Codec      string  `json:"codec,omitempty"`
+	Default    bool    `json:"default"`
+	Forced     bool    `json:"forced"`
+	External   bool    `json:"external"`
+	Selected   bool    `json:"selected"`
+	Width      int64   `json:"width,omitempty"`
+	Height     int64   `json:"height,omitempty"`
+	FPS        float64 `json:"fps,omitempty"`
+	Channels   int64   `json:"channels,omitempty"`
+	SampleRate int64   `json:"sampleRate,omitempty"`
+	Filename   string  `json:"filename,omitempty"` // caminho da legenda/áudio externo
+}
+
+// mpvTrack é um item de track-list como o MPV entrega
+type mpvTrack struct {
+	ID         int64   `json:"id"`
+	Type       string  `json:"type"`
+	Title      string  `json:"title"`
+	Lang       string  `json:"lang"`
+	Codec      string  `json:"codec"`
+	Default    bool    `json:"default"`
+	Forced     bool    `json:"forced"`
+	External   bool    `json:"external"`
+	Selected   bool    `json:"selected"`
+	Width      int64   `json:"demux-w"`
+	Height     int64   `json:"demux-h"`
+	FPS        float64 `json:"demux-fps"`
+	Channels   int64   `json:"demux-channel-count"`
+	SampleRate int64   `json:"demux-samplerate"`
+	Filename   string  `json:"external-filename"`
+}
+
+// track converte para o formato exposto pelo player
+func (m mpvTrack) track() Track {
+	t := Track{
+		ID:         m.ID,
+		Type:       m.Type,
+		Title:      m.Title,
+		Lang:       m.Lang,
+		Codec:      m.Codec,
+		Default:    m.Default,
+		Forced:     m.Forced,
+		External:   m.External,
+		Selected:   m.Selected,
+		Width:      m.Width,
+		Height:     m.Height,
+		FPS:        m.FPS,
+		Channels:   m.Channels,
+		SampleRate: m.SampleRate,
+		Filename:   m.Filename,
+	}
+	t.Label = trackLabel(t)
+	return t
+}
+
+// trackLabel monta o texto mostrado nos menus da GUI
+// Ex: "Português (Legendas completas) [forçada]", "jpn 2ch", "Faixa 3"
+func trackLabel(t Track) string {
+	var parts []string
+	switch {
+	case t.Title != "" && t.Lang != "":
+		parts = append(parts, fmt.Sprintf("%s (%s)", t.Title, t.Lang))
+	case t.Title != "":
+		parts = append(parts, t.Title)
+	case t.Lang != "":
+		parts = append(parts, t.Lang)
+	default:
+		parts = append(parts, fmt.Sprintf("Faixa %d", t.ID))
+	}
+
+	if t.Type == TrackAudio && t.Channels > 0 {
+		parts = append(parts, fmt.Sprintf("%dch", t.Channels))
+	}
+	if t.Type == TrackVideo && t.Height > 0 {
+		parts = append(parts, fmt.Sprintf("%dp", t.Height))
+	}
+	if t.Forced {
+		parts = append(parts, "[forçada]")
+	}
+	if t.External {
+		parts = append(parts, "[externa]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Chapter é um capítulo do arquivo (item de chapter-list do MPV)
+type Chapter struct {
+	Title string  `json:"title"`
+	Time  float64 `json:"time"`
+}
+
+// parseTrackList decodifica o JSON da propriedade track-list
+func parseTrackList(s string) ([]Track, error) {
+	var raw []mpvTrack
+	if s != "" {
+		if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, fmt.Errorf("track-list inválido: %w", err)
+		}
+	}
+
+	tracks := make([]Track, 0, len(raw))
+	for _, m := range raw {
+		tracks = append(tracks, m.track())
+	}
+	return tracks, nil
+}
+
+// parseChapterList decodifica o JSON da propriedade chapter-list
+func parseChapterList(s string) ([]Chapter, error) {
+	var chapters []Chapter
+	if s == "" {
+		return chapters, nil
+	}
+	if err := json.Unmarshal([]byte(s), &chapters); err != nil {
+		return nil, fmt.Errorf("chapter-list inválido: %w", err)
+	}
+	return chapters, nil
+}
+
+// getString lê uma propriedade de texto do engine do player
+func (p *Player) getString(name string) string {
+	return engineString(p.engine, name)
+}
+
+// engineString lê uma propriedade como texto ("" se indisponível)
+// Propriedades do tipo lista/mapa (track-list, chapter-list) vêm como JSON
+func engineString(e Engine, name string) string {
+	val, err := e.GetProperty(name, FormatString)
+	if err != nil {
+		return ""
+	}
+	s, _ := val.(string)
+	return s
+}
+
+// Tracks retorna todas as faixas do arquivo atual
+// Vem do observer de track-list; antes do Run, lê direto do MPV
+func (p *Player) Tracks() []Track {
+	p.mu.Lock()
+	tracks := append([]Track(nil), p.snapshot.Tracks...)
+	p.mu.Unlock()
+
+	if len(tracks) > 0 {
+		return tracks
+	}
+	tracks, err := parseTrackList(p.getString("track-list"))
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+	return tracks
+}
+
+// TracksOfType retorna apenas as faixas de um tipo (TrackAudio, TrackSubtitle...)
+func (p *Player) TracksOfType(kind string) []Track {
+	var out []Track
+	for _, t := range p.Tracks() {
+		if t.Type == kind {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SelectedTrack retorna a faixa em uso de um tipo (ok é false se nenhuma)
+func (p *Player) SelectedTrack(kind string) (Track, bool) {
+	for _, t := range p.TracksOfType(kind) {
+		if t.Selected {
+			return t, true
+		}
+	}
+	return Track{}, false
+}
@@ -0,0 +1,114 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UserConfig é a configuração persistida do usuário (player4k.json)
+type UserConfig struct {
+	// RecommendedMode é o modo escolhido pelo último benchmark
+	RecommendedMode PerformanceMode  `json:"recommendedMode,omitempty"`
+	Benchmark       *BenchmarkResult `json:"benchmark,omitempty"`
+
+	// Languages são as regras de áudio/legenda (veja LanguagePreferences)
+	Languages *LanguagePreferences `json:"languages,omitempty"`
+
+	// Skip define o que fazer com abertura, encerramento, prévia... (veja SkipPreferences)
+	Skip *SkipPreferences `json:"skip,omitempty"`
+
+	// WatchedThreshold é a fração a partir da qual o episódio conta como assistido (padrão 0.9)
+	WatchedThreshold float64 `json:"watchedThreshold,omitempty"`
+
+	// Seções de Settings (veja LoadSettings); ficam como JSON para que Save
+	// preserve exatamente o que o usuário escreveu
+	Playback    json.RawMessage `json:"playback,omitempty"`
+	Subtitles   json.RawMessage `json:"subtitles,omitempty"`
+	OSD         json.RawMessage `json:"osd,omitempty"`
+	Cache       json.RawMessage `json:"cache,omitempty"`
+	Screenshots json.RawMessage `json:"screenshots,omitempty"`
+	Network     json.RawMessage `json:"network,omitempty"`
+	Presets     json.RawMessage `json:"presets,omitempty"`
+}
+
+// settingsSections retorna as seções de Settings pelo nome no JSON
+func (c *UserConfig) settingsSections() map[string]json.RawMessage {
+	return map[string]json.RawMessage{
+		"playback":    c.Playback,
+		"subtitles":   c.Subtitles,
+		"osd":         c.OSD,
+		"cache":       c.Cache,
+		"screenshots": c.Screenshots,
+		"network":     c.Network,
+		"presets":     c.Presets,
+	}
+}
+
+// UserConfigDir retorna a pasta de configuração do player
+// Windows: %AppData%\player4k, Linux: ~/.config/player4k, macOS: ~/Library/Application Support/player4k
+func UserConfigDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("pasta de configuração indisponível: %w", err)
+	}
+	return filepath.Join(base, "player4k"), nil
+}
+
+// UserConfigPath retorna o caminho do arquivo player4k.json
+func UserConfigPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "player4k.json"), nil
+}
+
+// LoadUserConfig lê o player4k.json (configuração vazia se não existir)
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &UserConfig{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save grava o player4k.json, criando a pasta se necessário
+func (c *UserConfig) Save() error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic grava em arquivo temporário e renomeia para não corromper em caso de falha
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -2,7 +2,11 @@
 package player
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 )
 
 // WailsPlayer é o wrapper do player para uso com Wails
@@ -13,14 +17,47 @@ type WailsPlayer struct {
 
 // NewWailsPlayer cria um player para integração com Wails
 func NewWailsPlayer() (*WailsPlayer, error) {
-	p, err := New()
+	settings, settingsErr := LoadSettings()
+	bundle, err := OpenConfigBundleOrNone(settings.Playback.ConfigBundle)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+
+	p, err := NewWithBundle(bundle)
 	if err != nil {
 		return nil, err
 	}
 
+	if history, err := OpenDefaultWatchHistory(); err != nil {
+		fmt.Printf("⚠️ Histórico desligado: %v\n", err)
+	} else {
+		p.SetWatchHistory(history)
+	}
+	if intros, err := OpenDefaultIntroCache(); err != nil {
+		fmt.Printf("⚠️ Cache de aberturas desligado: %v\n", err)
+	} else {
+		p.SetIntroCache(intros)
+	}
+	if profiles, err := OpenDefaultProfileStore(); err != nil {
+		fmt.Printf("⚠️ Perfis por série desligados: %v\n", err)
+	} else {
+		p.SetProfileStore(profiles)
+	}
+	if settingsErr != nil {
+		fmt.Printf("⚠️ %v\n", settingsErr)
+	}
+	if err := p.ApplySettings(settings); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+
 	return &WailsPlayer{player: p}, nil
 }
 
+// WrapPlayer cria o wrapper sobre um Player existente (ex: servidor RPC, testes)
+func WrapPlayer(p *Player) *WailsPlayer {
+	return &WailsPlayer{player: p}
+}
+
 // --- Métodos expostos para o Frontend (Wails) ---
 
 // Initialize inicializa o player com um handle de janela
@@ -112,6 +149,11 @@ func (w *WailsPlayer) GetProgress() float64 {
 	return (w.player.GetPosition() / duration) * 100
 }
 
+// GetState retorna o estado da reprodução (idle, loading, playing, paused...)
+func (w *WailsPlayer) GetState() string {
+	return string(w.player.State())
+}
+
 // IsPlaying retorna se está reproduzindo
 func (w *WailsPlayer) IsPlaying() bool {
 	return w.player.IsPlaying()
@@ -125,18 +167,14 @@ func (w *WailsPlayer) IsPaused() bool {
 // --- Configurações de Qualidade ---
 
 // SetQualityMode define o modo de qualidade
-// mode: "low", "medium", "high"
+// mode: ID de qualquer preset carregado ("low", "medium", "high", ...)
+// IDs desconhecidos caem no modo "medium"
 func (w *WailsPlayer) SetQualityMode(mode string) {
-	switch mode {
-	case "low":
-		w.player.SetPerformanceMode(ModeLow)
-	case "medium":
-		w.player.SetPerformanceMode(ModeMedium)
-	case "high":
-		w.player.SetPerformanceMode(ModeHigh)
-	default:
-		w.player.SetPerformanceMode(ModeMedium)
+	m, ok := ParsePerformanceMode(mode)
+	if !ok {
+		m = ModeMedium
 	}
+	w.player.SetPerformanceMode(m)
 }
 
 // GetQualityMode retorna o modo de qualidade atual
@@ -156,6 +194,7 @@ func (w *WailsPlayer) GetQualityModes() []map[string]string {
 			"description": m.Description,
 			"icon":        m.Icon,
 			"gpuRequired": m.GPURequired,
+			"gpuTier":     strconv.Itoa(m.GPUTier),
 		}
 	}
 
@@ -167,23 +206,381 @@ func (w *WailsPlayer) SetAnimeMode(enable bool) {
 	w.player.SetAnimeMode(enable)
 }
 
+// SetAnimePreset aplica uma pipeline Anime4K
+// id: "A-HQ", "B-HQ", "C-HQ", "A+A-HQ", "B+B-HQ", "C+A-HQ" ou as versões "-Fast"
+func (w *WailsPlayer) SetAnimePreset(id string) error {
+	preset, ok := ParseAnimePreset(id)
+	if !ok {
+		return fmt.Errorf("preset Anime4K desconhecido: %s", id)
+	}
+	return w.player.SetAnimePreset(preset)
+}
+
+// GetAnimePreset retorna a pipeline Anime4K ativa ("" se desativada)
+func (w *WailsPlayer) GetAnimePreset() string {
+	return string(w.player.GetAnimePreset())
+}
+
+// GetAnimePresets retorna todas as pipelines Anime4K
+// "available" indica se todos os shaders da pipeline estão presentes
+func (w *WailsPlayer) GetAnimePresets() []map[string]string {
+	report := w.player.GetShaderReport()
+	pipelines := GetAnimePipelines()
+	result := make([]map[string]string, len(pipelines))
+
+	for i, pl := range pipelines {
+		result[i] = map[string]string{
+			"id":          string(pl.ID),
+			"mode":        pl.Mode,
+			"tier":        pl.Tier,
+			"description": pl.Description,
+			"available":   strconv.FormatBool(pl.Available(report)),
+		}
+	}
+
+	return result
+}
+
+// SetAdaptiveQuality liga/desliga o ajuste automático de qualidade por frames perdidos
+func (w *WailsPlayer) SetAdaptiveQuality(enable bool) {
+	w.player.EnableAdaptiveQuality(enable)
+}
+
 // EnableMotionSmoothing ativa/desativa interpolação de movimento
 func (w *WailsPlayer) EnableMotionSmoothing(enable bool) {
 	w.player.EnableInterpolation(enable)
 }
 
+// GetSettings retorna a configuração aplicada, chave por chave, com a origem de cada valor
+func (w *WailsPlayer) GetSettings() []SettingValue {
+	return w.player.Settings().Values()
+}
+
+// GetConfigBundle retorna a pasta de configuração do MPV em uso (null se nenhuma)
+func (w *WailsPlayer) GetConfigBundle() *ConfigBundle {
+	return w.player.ConfigBundle()
+}
+
+// GetBundleConflicts retorna as opções do mpv.conf trocadas pelo sistema e pelo player
+func (w *WailsPlayer) GetBundleConflicts() []BundleConflict {
+	return w.player.BundleConflicts()
+}
+
+// --- Playlist ---
+
+// LoadPlaylist troca a fila pelos episódios informados e começa pelo primeiro
+func (w *WailsPlayer) LoadPlaylist(paths []string) error {
+	return w.player.LoadFiles(paths...)
+}
+
+// LoadFolder abre todos os episódios de uma pasta, em ordem de episódio
+func (w *WailsPlayer) LoadFolder(dir string) error {
+	episodes, err := EpisodePlaylist(dir)
+	if err != nil {
+		return err
+	}
+	return w.player.LoadFiles(episodes...)
+}
+
+// ParseRelease extrai série, temporada, episódio, grupo e qualidade de um nome de arquivo
+func (w *WailsPlayer) ParseRelease(filename string) ReleaseInfo {
+	return ParseReleasePath(filename)
+}
+
+// Next pula para o próximo episódio
+func (w *WailsPlayer) Next() error {
+	return w.player.Next()
+}
+
+// Previous volta para o episódio anterior
+func (w *WailsPlayer) Previous() error {
+	return w.player.Previous()
+}
+
+// GetPlaylist retorna os itens, a posição atual e o modo de repetição
+func (w *WailsPlayer) GetPlaylist() map[string]interface{} {
+	pl := w.player.Playlist()
+	entries := pl.Entries()
+	if entries == nil {
+		entries = []PlaylistEntry{}
+	}
+	return map[string]interface{}{
+		"entries":  entries,
+		"position": pl.Position(),
+		"loop":     string(pl.Loop()),
+	}
+}
+
+// AddToPlaylist adiciona um episódio ao fim da fila
+func (w *WailsPlayer) AddToPlaylist(path string) error {
+	return w.player.Playlist().Append(path)
+}
+
+// InsertInPlaylist coloca um episódio na posição index
+func (w *WailsPlayer) InsertInPlaylist(index int, path string) error {
+	return w.player.Playlist().Insert(index, path)
+}
+
+// RemoveFromPlaylist remove o item index
+func (w *WailsPlayer) RemoveFromPlaylist(index int) error {
+	return w.player.Playlist().Remove(index)
+}
+
+// MovePlaylistItem leva o item from para a posição to
+func (w *WailsPlayer) MovePlaylistItem(from, to int) error {
+	return w.player.Playlist().Move(from, to)
+}
+
+// ShufflePlaylist embaralha a fila
+func (w *WailsPlayer) ShufflePlaylist() error {
+	return w.player.Playlist().Shuffle()
+}
+
+// PlayIndex toca o item index da fila
+func (w *WailsPlayer) PlayIndex(index int) error {
+	return w.player.Playlist().Play(index)
+}
+
+// SetLoopMode define a repetição: "none", "file" ou "playlist"
+func (w *WailsPlayer) SetLoopMode(mode string) error {
+	return w.player.Playlist().SetLoop(LoopMode(mode))
+}
+
+// SetAutoAdvance liga/desliga o avanço automático para o próximo episódio
+func (w *WailsPlayer) SetAutoAdvance(enable bool) {
+	w.player.SetAutoAdvance(enable)
+}
+
+// --- Pular abertura/encerramento ---
+
+// SetSkipSegments define os trechos do episódio atual (ex: vindos do AniSkip)
+func (w *WailsPlayer) SetSkipSegments(segments []SkipSegment) {
+	w.player.SetSkipSegments(segments)
+}
+
+// LoadAniSkipFile lê um JSON no formato do AniSkip e usa no episódio atual
+func (w *WailsPlayer) LoadAniSkipFile(path string) error {
+	segments, err := LoadAniSkipFile(path)
+	if err != nil {
+		return err
+	}
+	w.player.SetSkipSegments(segments)
+	return nil
+}
+
+// GetSkipSegments retorna os trechos do episódio atual (capítulos + externos)
+func (w *WailsPlayer) GetSkipSegments() []SkipSegment {
+	segments := w.player.SkipSegments()
+	if segments == nil {
+		return []SkipSegment{}
+	}
+	return segments
+}
+
+// SkipCurrentSegment pula o trecho em que a reprodução está (botão "Pular abertura")
+func (w *WailsPlayer) SkipCurrentSegment() error {
+	return w.player.SkipCurrentSegment()
+}
+
+// AnalyzeIntros procura abertura/encerramento dos episódios de uma pasta em segundo plano
+// O resultado chega no evento "introAnalysis" e fica no cache para as próximas reproduções
+func (w *WailsPlayer) AnalyzeIntros(dir string) error {
+	paths, err := EpisodePlaylist(dir)
+	if err != nil {
+		return err
+	}
+	return w.player.AnalyzeIntrosAsync(paths)
+}
+
+// SetSkipMode define o modo para todos os trechos: "prompt", "auto" ou "off"
+func (w *WailsPlayer) SetSkipMode(mode string) error {
+	m, err := ParseSkipMode(mode)
+	if err != nil {
+		return err
+	}
+	w.player.SetSkipPreferences(SkipPreferences{Mode: m})
+	return nil
+}
+
+// SetSkipPreferences define o modo de cada tipo de trecho
+func (w *WailsPlayer) SetSkipPreferences(prefs SkipPreferences) {
+	w.player.SetSkipPreferences(prefs)
+}
+
+// GetSkipPreferences retorna as preferências de pular trechos
+func (w *WailsPlayer) GetSkipPreferences() SkipPreferences {
+	return w.player.SkipPreferences()
+}
+
+// --- Histórico (continuar de onde parou) ---
+
+// Resume volta para onde o usuário parou no arquivo atual
+func (w *WailsPlayer) Resume() error {
+	return w.player.Resume()
+}
+
+// GetResumePosition retorna a posição salva do arquivo atual (0 se não houver)
+func (w *WailsPlayer) GetResumePosition() float64 {
+	return w.player.GetResumePosition()
+}
+
+// SetAutoResume faz cada arquivo continuar sozinho de onde parou
+func (w *WailsPlayer) SetAutoResume(enable bool) {
+	w.player.SetAutoResume(enable)
+}
+
+// GetWatchHistory retorna o histórico, do mais recente para o mais antigo
+func (w *WailsPlayer) GetWatchHistory() []WatchEntry {
+	h := w.player.WatchHistory()
+	if h == nil {
+		return []WatchEntry{}
+	}
+	return h.Entries()
+}
+
+// MarkWatched marca o episódio atual como assistido
+func (w *WailsPlayer) MarkWatched() error {
+	return w.player.MarkWatched()
+}
+
+// ForgetWatchEntry remove um item do histórico (key de WatchEntry)
+func (w *WailsPlayer) ForgetWatchEntry(key string) error {
+	h := w.player.WatchHistory()
+	if h == nil {
+		return fmt.Errorf("histórico desligado")
+	}
+	h.Forget(key)
+	return h.Save()
+}
+
+// --- Script Lua (goanime.lua) ---
+
+// ShowToast mostra uma notificação na janela do MPV por alguns segundos
+// Os pedidos feitos pelo script chegam no evento "scriptRequest"
+func (w *WailsPlayer) ShowToast(text string, seconds float64) error {
+	return w.player.ShowToast(text, time.Duration(seconds*float64(time.Second)))
+}
+
+// --- Perfis por série ---
+
+// GetSeriesProfiles retorna os perfis salvos, em ordem alfabética
+func (w *WailsPlayer) GetSeriesProfiles() []SeriesProfile {
+	s := w.player.ProfileStore()
+	if s == nil {
+		return []SeriesProfile{}
+	}
+	return s.List()
+}
+
+// GetCurrentProfile retorna o perfil da série do arquivo atual (nil se não houver)
+func (w *WailsPlayer) GetCurrentProfile() *SeriesProfile {
+	sp, ok := w.player.CurrentProfile()
+	if !ok {
+		return nil
+	}
+	return &sp
+}
+
+// ExportSeriesProfiles grava todos os perfis em um arquivo JSON
+func (w *WailsPlayer) ExportSeriesProfiles(path string) error {
+	s := w.player.ProfileStore()
+	if s == nil {
+		return fmt.Errorf("perfis por série desligados")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Export(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ResetSeriesProfile apaga o perfil de uma série (nome ou ID passado a SetSeries)
+func (w *WailsPlayer) ResetSeriesProfile(series string) error {
+	s := w.player.ProfileStore()
+	if s == nil {
+		return fmt.Errorf("perfis por série desligados")
+	}
+	if !s.Reset(series) {
+		return fmt.Errorf("nenhum perfil para %q", series)
+	}
+	return s.Save()
+}
+
+// ResetAllSeriesProfiles apaga todos os perfis e retorna quantos havia
+func (w *WailsPlayer) ResetAllSeriesProfiles() (int, error) {
+	s := w.player.ProfileStore()
+	if s == nil {
+		return 0, fmt.Errorf("perfis por série desligados")
+	}
+	n := s.ResetAll()
+	return n, s.Save()
+}
+
 // --- Legendas e Áudio ---
 
-// SetSubtitle define trilha de legenda por ID
+// GetTracks retorna todas as faixas (vídeo, áudio e legenda)
+func (w *WailsPlayer) GetTracks() []Track {
+	return nonNilTracks(w.player.Tracks())
+}
+
+// GetAudioTracks retorna as faixas de áudio para o menu de áudio
+func (w *WailsPlayer) GetAudioTracks() []Track {
+	return nonNilTracks(w.player.TracksOfType(TrackAudio))
+}
+
+// GetSubtitleTracks retorna as legendas para o menu de legendas
+func (w *WailsPlayer) GetSubtitleTracks() []Track {
+	return nonNilTracks(w.player.TracksOfType(TrackSubtitle))
+}
+
+// nonNilTracks garante [] em vez de null no JSON
+func nonNilTracks(tracks []Track) []Track {
+	if tracks == nil {
+		return []Track{}
+	}
+	return tracks
+}
+
+// SetSubtitle define trilha de legenda por ID (0 desliga)
 func (w *WailsPlayer) SetSubtitle(id int) {
 	w.player.SetSubtitleTrack(id)
 }
 
-// SetAudio define trilha de áudio por ID
+// SetAudio define trilha de áudio por ID (0 desliga)
 func (w *WailsPlayer) SetAudio(id int) {
 	w.player.SetAudioTrack(id)
 }
 
+// SetLanguagePreferences define as regras de áudio/legenda aplicadas ao abrir cada arquivo
+func (w *WailsPlayer) SetLanguagePreferences(prefs LanguagePreferences) {
+	w.player.SetLanguagePreferences(prefs)
+}
+
+// GetLanguagePreferences retorna as regras atuais
+func (w *WailsPlayer) GetLanguagePreferences() LanguagePreferences {
+	return w.player.LanguagePreferences()
+}
+
+// SetSeries informa a série do próximo episódio (overrides de idioma e perfil da série)
+// Pode ser o nome ou um ID estável do GoAnimeGUI; vazio usa a série do nome do arquivo
+func (w *WailsPlayer) SetSeries(name string) {
+	w.player.SetSeries(name)
+}
+
+// SetSubtitleDelay define o atraso da legenda em segundos (entra no perfil da série)
+func (w *WailsPlayer) SetSubtitleDelay(seconds float64) {
+	w.player.SetSubtitleDelay(seconds)
+}
+
+// GetSubtitleDelay retorna o atraso da legenda em segundos
+func (w *WailsPlayer) GetSubtitleDelay() float64 {
+	return w.player.GetSubtitleDelay()
+}
+
 // LoadExternalSubtitle carrega legenda externa
 func (w *WailsPlayer) LoadExternalSubtitle(path string) error {
 	return w.player.LoadSubtitle(path)
@@ -196,41 +593,69 @@ func (w *WailsPlayer) GetDroppedFrames() int64 {
 	return w.player.GetDroppedFrames()
 }
 
+// VerifyShaders verifica shaders (existência e SHA-256) e retorna o relatório
+func (w *WailsPlayer) VerifyShaders() ShaderReport {
+	return w.player.VerifyShaders()
+}
+
+// GetSnapshot retorna o estado da reprodução (posição, cache, capítulo, faixas...)
+func (w *WailsPlayer) GetSnapshot() PlaybackSnapshot {
+	return w.player.Snapshot()
+}
+
 // GetStats retorna estatísticas do player
 func (w *WailsPlayer) GetStats() map[string]interface{} {
+	snap := w.player.Snapshot()
 	return map[string]interface{}{
-		"position":      w.player.GetPosition(),
-		"duration":      w.player.GetDuration(),
-		"droppedFrames": w.player.GetDroppedFrames(),
+		"state":         string(snap.State),
+		"position":      snap.Position,
+		"duration":      snap.Duration,
+		"droppedFrames": snap.DroppedFrames,
+		"buffering":     snap.Buffering,
+		"cacheDuration": snap.CacheDuration,
+		"chapter":       snap.Chapter,
 		"mode":          string(w.player.GetCurrentMode()),
+		"animePreset":   string(w.player.GetAnimePreset()),
+		"adaptive":      w.player.IsAdaptiveQuality(),
 		"isPlaying":     w.player.IsPlaying(),
 		"isPaused":      w.player.IsPaused(),
 		"volume":        w.player.GetVolume(),
 	}
 }
 
-// Destroy libera recursos
-func (w *WailsPlayer) Destroy() {
-	if w.player != nil {
-		w.player.Destroy()
-	}
+// Run executa o loop de eventos até ctx ser cancelado ou o player ser fechado
+// No Wails, chame em uma goroutine a partir do OnStartup
+func (w *WailsPlayer) Run(ctx context.Context) error {
+	return w.player.Run(ctx)
 }
 
-// --- Callbacks para eventos (para uso interno) ---
+// Close para o loop de eventos e libera o libmpv (pode ser chamado mais de uma vez)
+func (w *WailsPlayer) Close() error {
+	return w.player.Close()
+}
 
-// SetOnTimeUpdate define callback para atualização de tempo
-func (w *WailsPlayer) SetOnTimeUpdate(callback func(position, duration float64)) {
-	w.player.OnTimeUpdate = callback
+// Destroy libera recursos
+func (w *WailsPlayer) Destroy() {
+	w.Close()
 }
 
-// SetOnStateChange define callback para mudança de estado
-func (w *WailsPlayer) SetOnStateChange(callback func(state string)) {
-	w.player.OnStateChange = callback
+// --- Eventos ---
+
+// OnEvent repassa os eventos do player para emit até ctx ser cancelado
+// Feito para runtime.EventsEmit do Wails: emit(ctx, "player:"+name, data)
+// Os nomes são os de EventType (fileLoaded, ended, timeUpdate, stateChanged...)
+func (w *WailsPlayer) OnEvent(ctx context.Context, emit func(name string, data interface{})) {
+	events := w.player.Subscribe(ctx)
+	go func() {
+		for ev := range events {
+			emit(string(ev.Type()), ev)
+		}
+	}()
 }
 
-// SetOnError define callback para erros
-func (w *WailsPlayer) SetOnError(callback func(err error)) {
-	w.player.OnError = callback
+// Subscribe retorna os eventos tipados do player (veja Player.Subscribe)
+func (w *WailsPlayer) Subscribe(ctx context.Context) <-chan Event {
+	return w.player.Subscribe(ctx)
 }
 
 // PrintInfo imprime informações do player (debug)